@@ -6,10 +6,16 @@ import (
 
 	"github.com/ecommerce/config"
 	"github.com/ecommerce/domain/auth"
+	"github.com/ecommerce/domain/cart"
 	"github.com/ecommerce/domain/category"
 	"github.com/ecommerce/domain/file"
+	"github.com/ecommerce/domain/merchant"
+	"github.com/ecommerce/domain/order"
 	"github.com/ecommerce/domain/product"
+	"github.com/ecommerce/infra/mail"
 	"github.com/ecommerce/infra/middleware"
+	"github.com/ecommerce/infra/notifier"
+	"github.com/ecommerce/infra/payment"
 	"github.com/ecommerce/infra/storage/images"
 	"github.com/ecommerce/pkg/database"
 	"github.com/gofiber/fiber/v2"
@@ -33,6 +39,7 @@ func main() {
 	}
 	jwt := config.Cfg.JWT
 	middleware.SetJWTSecretKey(jwt.Secret)
+	middleware.SetJWTIssuer(jwt.Issuer, jwt.Audience)
 
 	db, err := database.ConnectSQLXPostgres(config.Cfg.DB)
 	if err != nil {
@@ -43,16 +50,29 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	middleware.SetRedisClient(rdb)
 
 	cloudClient, err := images.CloudinaryStorage(config.Cfg.FileCloudStorage)
 	if err != nil {
 		panic(err)
 	}
 
-	auth.RegisterServiceAuth(app, auth.DB{Dbx: db, Redis: rdb, Cfg: config.Cfg.JWT})
+	mailSender := mail.NewSMTP(config.Cfg.Mail)
+
+	auth.RegisterServiceAuth(app, auth.DB{Dbx: db, Redis: rdb, Mail: mailSender, Cfg: config.Cfg.JWT, AuthCfg: config.Cfg.Auth})
 	category.RegisterServiceCategory(app, category.DB{Dbx: db})
 	product.RegisterServiceProduct(app, product.DB{Dbx: db})
+	merchant.RegisterServiceMerchant(app, merchant.DB{Dbx: db, Redis: rdb, Cfg: config.Cfg.JWT})
+	cart.RegisterServiceCart(app, cart.DB{Dbx: db, Redis: rdb, Cfg: config.Cfg.Cart})
+	order.RegisterServiceOrder(app, order.DB{Dbx: db, Redis: rdb, Gateway: payment.NewHTTPGateway(config.Cfg.Payment)})
 	file.RegisterServiceFile(app, cloudClient)
 
+	// with prefork every child runs main too, the background jobs belong to the master only
+	if !fiber.IsChild() {
+		go product.RunPurgeJob(context.Background(), product.DB{Dbx: db}, config.Cfg.Product)
+		go product.RunLowStockJob(context.Background(), product.DB{Dbx: db}, notifier.New(config.Cfg.Product.LowStockNotifier, mailSender), config.Cfg.Product)
+		go order.RunExpiryJob(context.Background(), order.DB{Dbx: db, Redis: rdb, Gateway: payment.NewHTTPGateway(config.Cfg.Payment)}, config.Cfg.Order)
+	}
+
 	app.Listen(config.Cfg.App.Port)
 }
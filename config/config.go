@@ -10,7 +10,13 @@ type Config struct {
 	App              App              `yaml:"app"`
 	DB               DB               `yaml:"db"`
 	JWT              JWT              `yaml:"jwt"`
+	Auth             Auth             `yaml:"auth"`
+	Mail             Mail             `yaml:"mail"`
 	Redis            Redis            `yaml:"redis"`
+	Product          Product          `yaml:"product"`
+	Cart             Cart             `yaml:"cart"`
+	Order            Order            `yaml:"order"`
+	Payment          Payment          `yaml:"payment"`
 	FileCloudStorage FileCloudStorage `yaml:"fileCloudStorage"`
 }
 
@@ -30,8 +36,27 @@ type DB struct {
 }
 
 type JWT struct {
-	Secret            string `yaml:"secret"`
-	TokenLifeTimeHour int    `yaml:"tokenLifeTimeHour"`
+	Secret                   string `yaml:"secret"`
+	Issuer                   string `yaml:"issuer"`
+	Audience                 string `yaml:"audience"`
+	TokenLifeTimeHour        int    `yaml:"tokenLifeTimeHour"`
+	RefreshTokenLifeTimeHour int    `yaml:"refreshTokenLifeTimeHour"`
+}
+
+type Auth struct {
+	ResetTokenLifeTimeMinute int    `yaml:"resetTokenLifeTimeMinute"`
+	ResetPasswordURL         string `yaml:"resetPasswordURL"`
+	VerifyTokenLifeTimeHour  int    `yaml:"verifyTokenLifeTimeHour"`
+	VerifyEmailURL           string `yaml:"verifyEmailURL"`
+	VerificationPolicy       string `yaml:"verificationPolicy"`
+}
+
+type Mail struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
 }
 
 type Redis struct {
@@ -41,6 +66,31 @@ type Redis struct {
 	MaxIdle  int    `yaml:"maxIdle"`
 }
 
+type Product struct {
+	PurgeRetentionDay      int    `yaml:"purgeRetentionDay"`
+	PurgeIntervalMinute    int    `yaml:"purgeIntervalMinute"`
+	LowStockIntervalMinute int    `yaml:"lowStockIntervalMinute"`
+	LowStockNotifier       string `yaml:"lowStockNotifier"`
+}
+
+type Cart struct {
+	ExpireDay int `yaml:"expireDay"`
+}
+
+type Order struct {
+	PaymentWindowMinute  int `yaml:"paymentWindowMinute"`
+	PaymentGraceMinute   int `yaml:"paymentGraceMinute"`
+	ExpiryIntervalMinute int `yaml:"expiryIntervalMinute"`
+}
+
+type Payment struct {
+	BaseURL               string `yaml:"baseURL"`
+	SecretKey             string `yaml:"secretKey"`
+	CallbackSecret        string `yaml:"callbackSecret"`
+	InvoiceDurationMinute int    `yaml:"invoiceDurationMinute"`
+	Timeout               int    `yaml:"timeout"`
+}
+
 type FileCloudStorage struct {
 	CloudinaryName      string `yaml:"cloudinaryName"`
 	CloudinaryAPIKey    string `yaml:"cloudinaryAPIKey"`
@@ -3,6 +3,8 @@ package auth
 import (
 	"github.com/ecommerce/config"
 	"github.com/ecommerce/domain/auth/repository"
+	"github.com/ecommerce/entity"
+	"github.com/ecommerce/infra/mail"
 	"github.com/ecommerce/infra/middleware"
 	"github.com/go-redis/redis/v8"
 	"github.com/gofiber/fiber/v2"
@@ -10,15 +12,21 @@ import (
 )
 
 type DB struct {
-	Dbx   *sqlx.DB
-	Redis *redis.Client
-	Cfg   config.JWT
+	Dbx     *sqlx.DB
+	Redis   *redis.Client
+	Mail    mail.Sender
+	Cfg     config.JWT
+	AuthCfg config.Auth
 }
 
 func RegisterServiceAuth(router fiber.Router, db DB) {
+	if err := entity.NewAuth().ValidateVerificationPolicy(db.AuthCfg.VerificationPolicy); err != nil {
+		panic(err)
+	}
+
 	authRepository := repository.NewAuthRepository(db.Dbx)
 	redisRepository := repository.NewRedisRepository(db.Redis)
-	service := NewAuthService(authRepository, redisRepository, db.Cfg)
+	service := NewAuthService(authRepository, redisRepository, db.Mail, db.Cfg, db.AuthCfg)
 	// service := NewAuthService(authRepository, redisRepository)
 	handler := NewAuthHandler(service)
 
@@ -26,7 +34,11 @@ func RegisterServiceAuth(router fiber.Router, db DB) {
 	{
 		authRouter.Post("/register", handler.Register)
 		authRouter.Post("/login", handler.Login)
-		authRouter.Patch("/role", middleware.AuthMiddleware(), handler.UpdateRole)
-		// authRouter.Patch("/role", handler.UpdateRole)
+		authRouter.Post("/refresh", handler.Refresh)
+		authRouter.Post("/logout", middleware.AuthMiddleware(), handler.Logout)
+		authRouter.Post("/password/forgot", handler.ForgotPassword)
+		authRouter.Post("/password/reset", handler.ResetPassword)
+		authRouter.Get("/verify", handler.VerifyEmail)
+		authRouter.Post("/verify/resend", handler.ResendVerification)
 	}
 }
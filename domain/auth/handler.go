@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ecommerce/dto"
 	"github.com/ecommerce/entity"
@@ -53,27 +54,137 @@ func (a AuthHandler) Login(c *fiber.Ctx) error {
 		return WriteError(c, err)
 	}
 
-	response, accessToken, err := a.service.Login(c.UserContext(), model)
+	response, accessToken, refreshToken, err := a.service.Login(c.UserContext(), model)
 	if err != nil {
 		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
 		return WriteError(c, err)
 	}
 
 	payload := dto.LoginResponse{
-		AccessToken: accessToken,
-		Role:        response.Role,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Role:         response.Role,
 	}
 
 	return WriteSuccess(c, "login success", payload, fiber.StatusOK)
 }
 
-func (a AuthHandler) UpdateRole(c *fiber.Ctx) error {
-	email := c.Locals("email").(string)
+func (a AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req dto.RefreshTokenRequest
 
-	if err := a.service.UpdateRole(c.UserContext(), email); err != nil {
+	if err := c.BodyParser(&req); err != nil {
+		return WriteError(c, err)
+	}
+
+	if err := entity.NewAuth().ValidateRefreshToken(req); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, accessToken, refreshToken, err := a.service.Refresh(c.UserContext(), req.RefreshToken)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	payload := dto.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Role:         response.Role,
+	}
+
+	return WriteSuccess(c, "refresh token success", payload, fiber.StatusOK)
+}
+
+func (a AuthHandler) Logout(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+	sessionID := c.Locals("session_id").(string)
+	jti := c.Locals("jti").(string)
+	expiresAt := c.Locals("expires_at").(time.Time)
+
+	if err := a.service.Logout(c.UserContext(), id, sessionID, jti, expiresAt); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "logout success", nil, fiber.StatusOK)
+}
+
+func (a AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req dto.ForgotPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return WriteError(c, err)
+	}
+
+	model, err := entity.NewAuth().ValidateForgotPassword(req)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := a.service.ForgotPassword(c.UserContext(), model.Email); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "if the email is registered, a reset link has been sent", nil, fiber.StatusOK)
+}
+
+func (a AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req dto.ResetPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return WriteError(c, err)
+	}
+
+	model, err := entity.NewAuth().ValidateResetPassword(req)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := a.service.ResetPassword(c.UserContext(), req.Token, model); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "reset password success", nil, fiber.StatusOK)
+}
+
+func (a AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	token := c.Query("token")
+
+	if err := entity.NewAuth().ValidateVerifyToken(token); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := a.service.VerifyEmail(c.UserContext(), token); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "verify email success", nil, fiber.StatusOK)
+}
+
+func (a AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	var req dto.ResendVerificationRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return WriteError(c, err)
+	}
+
+	model, err := entity.NewAuth().ValidateResendVerification(req)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := a.service.ResendVerification(c.UserContext(), model.Email); err != nil {
 		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
 		return WriteError(c, err)
 	}
 
-	return WriteSuccess(c, "update role success", nil, fiber.StatusOK)
+	return WriteSuccess(c, "if the email is registered and not verified yet, a verification link has been sent", nil, fiber.StatusOK)
 }
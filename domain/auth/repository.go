@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/ecommerce/entity"
 )
@@ -9,10 +10,19 @@ import (
 type Repository interface {
 	Create(ctx context.Context, user entity.Auth) (err error)
 	GetByEmail(ctx context.Context, email string) (user entity.Auth, err error)
-	UpdateRole(ctx context.Context, id string) (err error)
+	UpdatePassword(ctx context.Context, id, password string) (err error)
+	VerifyEmail(ctx context.Context, id string) (err error)
 }
 
 type RedisRepository interface {
-	Set(ctx context.Context, timeLimit int, token, id, email string) (err error)
-	Get(ctx context.Context, id, email string) (token string, err error)
+	SetSession(ctx context.Context, timeLimit int, refreshToken string, session entity.Session) (err error)
+	ConsumeRefreshToken(ctx context.Context, refreshToken string) (session entity.Session, err error)
+	DeleteSession(ctx context.Context, id, sessionID string) (err error)
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) (err error)
+	RevokeUserTokens(ctx context.Context, timeLimit int, id string) (err error)
+	DeleteUserSessions(ctx context.Context, id string) (err error)
+	SetPasswordResetToken(ctx context.Context, timeLimit int, token, email string) (err error)
+	ConsumePasswordResetToken(ctx context.Context, token string) (email string, err error)
+	SetEmailVerificationToken(ctx context.Context, timeLimit int, token, email string) (err error)
+	ConsumeEmailVerificationToken(ctx context.Context, token string) (email string, err error)
 }
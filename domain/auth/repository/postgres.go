@@ -19,10 +19,6 @@ func NewAuthRepository(db *sqlx.DB) AuthRepository {
 	}
 }
 
-const (
-	RoleMerchant = "merchant"
-)
-
 func (r AuthRepository) Create(ctx context.Context, user entity.Auth) (err error) {
 	stmt, err := r.db.PrepareNamedContext(ctx, queryCreate)
 	if err != nil {
@@ -49,14 +45,29 @@ func (r AuthRepository) GetByEmail(ctx context.Context, email string) (user enti
 	return
 }
 
-func (r AuthRepository) UpdateRole(ctx context.Context, id string) (err error) {
-	stmt, err := r.db.PreparexContext(ctx, queryUpdateRole)
+func (r AuthRepository) UpdatePassword(ctx context.Context, id, password string) (err error) {
+	stmt, err := r.db.PreparexContext(ctx, queryUpdatePassword)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, password, id)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r AuthRepository) VerifyEmail(ctx context.Context, id string) (err error) {
+	stmt, err := r.db.PreparexContext(ctx, queryVerifyEmail)
 	if err != nil {
 		return
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, RoleMerchant, id)
+	_, err = stmt.ExecContext(ctx, id)
 	if err != nil {
 		return
 	}
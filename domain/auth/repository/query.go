@@ -10,11 +10,16 @@ const (
 		id,
 		email,
 		password,
-		role
+		role,
+		email_verified_at
 	FROM auth
 	WHERE email = $1
 	`
-	queryUpdateRole = `
-	UPDATE auth SET role = $1 WHERE id = $2
+	queryVerifyEmail = `
+	UPDATE auth SET email_verified_at = NOW(), updated_at = NOW() WHERE id = $1 AND email_verified_at IS NULL
+	`
+
+	queryUpdatePassword = `
+	UPDATE auth SET password = $1, updated_at = NOW() WHERE id = $2
 	`
 )
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/ecommerce/entity"
+	"github.com/ecommerce/infra/middleware"
 	"github.com/go-redis/redis/v8"
 	"github.com/sirupsen/logrus"
 )
@@ -19,10 +22,30 @@ func NewRedisRepository(redis *redis.Client) RedisRepository {
 	}
 }
 
-func (r RedisRepository) Set(ctx context.Context, timeLimit int, token, id, email string) (err error) {
-	key := fmt.Sprintf("%s:%s", email, id)
+func refreshTokenKey(refreshToken string) string {
+	return fmt.Sprintf("refresh:%s", refreshToken)
+}
+
+func passwordResetKey(token string) string {
+	return fmt.Sprintf("password_reset:%s", token)
+}
+
+func emailVerificationKey(token string) string {
+	return fmt.Sprintf("email_verification:%s", token)
+}
+
+func (r RedisRepository) SetSession(ctx context.Context, timeLimit int, refreshToken string, session entity.Session) (err error) {
+	value, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+
 	ttl := time.Duration(timeLimit) * time.Hour
-	err = r.redis.Set(ctx, key, token, ttl).Err()
+	pipe := r.redis.TxPipeline()
+	pipe.Set(ctx, middleware.SessionKey(session.ID, session.SessionID), refreshToken, ttl)
+	pipe.Set(ctx, refreshTokenKey(refreshToken), value, ttl)
+
+	_, err = pipe.Exec(ctx)
 	if err != nil {
 		logrus.Error(err)
 		return
@@ -31,12 +54,126 @@ func (r RedisRepository) Set(ctx context.Context, timeLimit int, token, id, emai
 	return
 }
 
-func (r RedisRepository) Get(ctx context.Context, id, email string) (token string, err error) {
-	key := fmt.Sprintf("%s:%s", email, id)
-	token, err = r.redis.Get(ctx, key).Result()
+func (r RedisRepository) ConsumeRefreshToken(ctx context.Context, refreshToken string) (session entity.Session, err error) {
+	value, err := r.redis.GetDel(ctx, refreshTokenKey(refreshToken)).Result()
 	if err != nil {
-		return "", nil
+		if err == redis.Nil {
+			return entity.Session{}, nil
+		}
+		return
+	}
+
+	err = json.Unmarshal([]byte(value), &session)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r RedisRepository) DeleteSession(ctx context.Context, id, sessionID string) (err error) {
+	refreshToken, err := r.redis.GetDel(ctx, middleware.SessionKey(id, sessionID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil
+		}
+		return
+	}
+
+	err = r.redis.Del(ctx, refreshTokenKey(refreshToken)).Err()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
+
+func (r RedisRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) (err error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return
+	}
+
+	err = r.redis.Set(ctx, middleware.RevokedTokenKey(jti), 1, ttl).Err()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
+
+func (r RedisRepository) RevokeUserTokens(ctx context.Context, timeLimit int, id string) (err error) {
+	ttl := time.Duration(timeLimit) * time.Hour
+	err = r.redis.Set(ctx, middleware.RevokedBeforeKey(id), time.Now().UnixMilli(), ttl).Err()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
+
+func (r RedisRepository) DeleteUserSessions(ctx context.Context, id string) (err error) {
+	iter := r.redis.Scan(ctx, 0, middleware.SessionKey(id, "*"), 100).Iterator()
+	for iter.Next(ctx) {
+		refreshToken, err := r.redis.GetDel(ctx, iter.Val()).Result()
+		if err != nil {
+			if err == redis.Nil {
+				continue
+			}
+			return err
+		}
+
+		if err = r.redis.Del(ctx, refreshTokenKey(refreshToken)).Err(); err != nil {
+			logrus.Error(err)
+			return err
+		}
+	}
+
+	err = iter.Err()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r RedisRepository) SetPasswordResetToken(ctx context.Context, timeLimit int, token, email string) (err error) {
+	return r.setOneTimeToken(ctx, passwordResetKey(token), email, time.Duration(timeLimit)*time.Minute)
+}
+
+func (r RedisRepository) ConsumePasswordResetToken(ctx context.Context, token string) (email string, err error) {
+	return r.consumeOneTimeToken(ctx, passwordResetKey(token))
+}
+
+func (r RedisRepository) SetEmailVerificationToken(ctx context.Context, timeLimit int, token, email string) (err error) {
+	return r.setOneTimeToken(ctx, emailVerificationKey(token), email, time.Duration(timeLimit)*time.Hour)
+}
+
+func (r RedisRepository) ConsumeEmailVerificationToken(ctx context.Context, token string) (email string, err error) {
+	return r.consumeOneTimeToken(ctx, emailVerificationKey(token))
+}
+
+func (r RedisRepository) setOneTimeToken(ctx context.Context, key, value string, ttl time.Duration) (err error) {
+	err = r.redis.Set(ctx, key, value, ttl).Err()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
+
+func (r RedisRepository) consumeOneTimeToken(ctx context.Context, key string) (value string, err error) {
+	value, err = r.redis.GetDel(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return
 	}
 
-	return token, nil
+	return value, nil
 }
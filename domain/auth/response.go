@@ -20,10 +20,24 @@ func WriteError(c *fiber.Ctx, err error) error {
 		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40004", nil)
 	case err == entity.ErrUserAlreadyMerchant:
 		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40001", nil)
+	case err == entity.ErrRefreshTokenIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40005", nil)
+	case err == entity.ErrResetTokenIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40006", nil)
+	case err == entity.ErrInvalidResetToken:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40007", nil)
+	case err == entity.ErrVerifyTokenIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40008", nil)
+	case err == entity.ErrInvalidVerifyToken:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40009", nil)
+	case err == entity.ErrEmailNotVerified:
+		return write(c, http.StatusForbidden, "forbidden", err.Error(), "40301", nil)
 	case err == entity.ErrEmailAlreadyUsed:
 		return write(c, http.StatusConflict, "duplicate entry", err.Error(), "40901", nil)
 	case err == entity.ErrInvalidEmailOrPassword:
 		return write(c, http.StatusUnauthorized, "unauthorized", err.Error(), "40101", nil)
+	case err == entity.ErrInvalidRefreshToken:
+		return write(c, http.StatusUnauthorized, "unauthorized", err.Error(), "40102", nil)
 	default:
 		if iSSQLIntegrityConstraintViolation(err) {
 			return write(c, http.StatusInternalServerError, "internal server error", "error repository", "50001", nil)
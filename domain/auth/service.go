@@ -2,28 +2,41 @@ package auth
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/ecommerce/config"
 	"github.com/ecommerce/entity"
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/ecommerce/infra/mail"
 )
 
 type Service interface {
 	Register(ctx context.Context, req entity.Auth) (err error)
-	Login(ctx context.Context, req entity.Auth) (response entity.Auth, accessToken string, err error)
-	UpdateRole(ctx context.Context, email string) (err error)
+	Login(ctx context.Context, req entity.Auth) (response entity.Auth, accessToken, refreshToken string, err error)
+	Refresh(ctx context.Context, refreshToken string) (response entity.Auth, accessToken, newRefreshToken string, err error)
+	Logout(ctx context.Context, id, sessionID, jti string, expiresAt time.Time) (err error)
+	ForgotPassword(ctx context.Context, email string) (err error)
+	ResetPassword(ctx context.Context, token string, req entity.Auth) (err error)
+	VerifyEmail(ctx context.Context, token string) (err error)
+	ResendVerification(ctx context.Context, email string) (err error)
 }
 
 type AuthService struct {
 	repository Repository
 	redis      RedisRepository
+	mail       mail.Sender
 	cfg        config.JWT
+	authCfg    config.Auth
 }
 
-func NewAuthService(repository Repository, redis RedisRepository, cfg config.JWT) AuthService {
+func NewAuthService(repository Repository, redis RedisRepository, mail mail.Sender, cfg config.JWT, authCfg config.Auth) AuthService {
 	return AuthService{
 		repository: repository,
 		redis:      redis,
+		mail:       mail,
 		cfg:        cfg,
+		authCfg:    authCfg,
 	}
 }
 
@@ -45,10 +58,14 @@ func (a AuthService) Register(ctx context.Context, req entity.Auth) (err error)
 		return
 	}
 
+	if err := a.sendVerificationEmail(ctx, req.Email); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+	}
+
 	return
 }
 
-func (a AuthService) Login(ctx context.Context, req entity.Auth) (response entity.Auth, accessToken string, err error) {
+func (a AuthService) Login(ctx context.Context, req entity.Auth) (response entity.Auth, accessToken, refreshToken string, err error) {
 	user, err := a.repository.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return
@@ -59,39 +76,220 @@ func (a AuthService) Login(ctx context.Context, req entity.Auth) (response entit
 	}
 
 	if !req.ValidatePasswordFromPlainText(req.Password, user.Password) {
-		return response, accessToken, entity.ErrInvalidEmailOrPassword
+		return response, accessToken, refreshToken, entity.ErrInvalidEmailOrPassword
 	}
 
-	accessToken, err = a.redis.Get(ctx, user.ID, user.Email)
+	if a.authCfg.VerificationPolicy == entity.VerificationPolicyLogin {
+		if err = user.CheckEmailVerified(); err != nil {
+			return
+		}
+	}
+
+	accessToken, refreshToken, err = a.issueTokens(ctx, user, user.NewSession())
 	if err != nil {
 		return
 	}
 
-	if accessToken == "" {
-		accessToken, err = user.GenerateAccessToken(accessToken)
-		if err != nil {
-			return
-		}
+	return user, accessToken, refreshToken, nil
+}
 
-		if err = a.redis.Set(ctx, a.cfg.TokenLifeTimeHour, accessToken, user.ID, user.Email); err != nil {
-			return
+func (a AuthService) Refresh(ctx context.Context, refreshToken string) (response entity.Auth, accessToken, newRefreshToken string, err error) {
+	session, err := a.redis.ConsumeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return
+	}
+
+	if session.ID == "" {
+		return response, accessToken, newRefreshToken, entity.ErrInvalidRefreshToken
+	}
+
+	user, err := a.repository.GetByEmail(ctx, session.Email)
+	if err != nil {
+		return
+	}
+
+	if user.ID != session.ID {
+		return response, accessToken, newRefreshToken, entity.ErrInvalidRefreshToken
+	}
+
+	accessToken, newRefreshToken, err = a.issueTokens(ctx, user, session)
+	if err != nil {
+		return
+	}
+
+	return user, accessToken, newRefreshToken, nil
+}
+
+func (a AuthService) Logout(ctx context.Context, id, sessionID, jti string, expiresAt time.Time) (err error) {
+	if err = a.redis.DeleteSession(ctx, id, sessionID); err != nil {
+		return
+	}
+
+	if err = a.redis.RevokeToken(ctx, jti, expiresAt); err != nil {
+		return
+	}
+
+	return
+}
+
+func (a AuthService) issueTokens(ctx context.Context, user entity.Auth, session entity.Session) (accessToken, refreshToken string, err error) {
+	accessToken, err = user.GenerateAccessToken(session.SessionID, a.cfg.TokenLifeTimeHour)
+	if err != nil {
+		return
+	}
+
+	refreshToken, err = user.GenerateRandomToken()
+	if err != nil {
+		return
+	}
+
+	if err = a.redis.SetSession(ctx, a.cfg.RefreshTokenLifeTimeHour, refreshToken, session); err != nil {
+		return
+	}
+
+	return
+}
+
+func (a AuthService) ForgotPassword(ctx context.Context, email string) (err error) {
+	user, err := a.repository.GetByEmail(ctx, email)
+	if err != nil {
+		return
+	}
+
+	// unknown emails get the same response and the reset is sent in the
+	// background, so neither its latency nor its failure reveals an account
+	if user.ID == "" {
+		return
+	}
+
+	go func() {
+		if err := a.sendPasswordReset(context.WithoutCancel(ctx), user.Email); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
 		}
+	}()
+
+	return
+}
+
+func (a AuthService) sendPasswordReset(ctx context.Context, email string) (err error) {
+	token, err := entity.NewAuth().GenerateRandomToken()
+	if err != nil {
+		return
+	}
+
+	if err = a.redis.SetPasswordResetToken(ctx, a.authCfg.ResetTokenLifeTimeMinute, token, email); err != nil {
+		return
 	}
 
-	return user, accessToken, nil
+	body := fmt.Sprintf(
+		"We received a request to reset your password.\n\nOpen the link below within %d minutes to choose a new one:\n%s?token=%s\n\nIf you did not ask for this, you can ignore this email.",
+		a.authCfg.ResetTokenLifeTimeMinute, a.authCfg.ResetPasswordURL, token,
+	)
+
+	if err = a.mail.Send(ctx, email, "Reset your password", body); err != nil {
+		return
+	}
+
+	return
 }
 
-func (a AuthService) UpdateRole(ctx context.Context, email string) (err error) {
+func (a AuthService) ResetPassword(ctx context.Context, token string, req entity.Auth) (err error) {
+	email, err := a.redis.ConsumePasswordResetToken(ctx, token)
+	if err != nil {
+		return
+	}
+
+	if email == "" {
+		return entity.ErrInvalidResetToken
+	}
+
 	user, err := a.repository.GetByEmail(ctx, email)
 	if err != nil {
 		return
 	}
 
-	if err = entity.NewAuth().ValidateUserRole(user.Role); err != nil {
+	if user.ID == "" {
+		return entity.ErrInvalidResetToken
+	}
+
+	if err = req.EncryptPassword(); err != nil {
+		return
+	}
+
+	if err = a.repository.UpdatePassword(ctx, user.ID, req.Password); err != nil {
 		return
 	}
 
-	if err = a.repository.UpdateRole(ctx, user.ID); err != nil {
+	if err = a.redis.DeleteUserSessions(ctx, user.ID); err != nil {
+		return
+	}
+
+	if err = a.redis.RevokeUserTokens(ctx, a.cfg.TokenLifeTimeHour, user.ID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (a AuthService) VerifyEmail(ctx context.Context, token string) (err error) {
+	email, err := a.redis.ConsumeEmailVerificationToken(ctx, token)
+	if err != nil {
+		return
+	}
+
+	if email == "" {
+		return entity.ErrInvalidVerifyToken
+	}
+
+	user, err := a.repository.GetByEmail(ctx, email)
+	if err != nil {
+		return
+	}
+
+	if user.ID == "" {
+		return entity.ErrInvalidVerifyToken
+	}
+
+	if err = a.repository.VerifyEmail(ctx, user.ID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (a AuthService) ResendVerification(ctx context.Context, email string) (err error) {
+	user, err := a.repository.GetByEmail(ctx, email)
+	if err != nil {
+		return
+	}
+
+	if user.ID == "" || user.EmailVerifiedAt != nil {
+		return
+	}
+
+	if err = a.sendVerificationEmail(ctx, user.Email); err != nil {
+		return
+	}
+
+	return
+}
+
+func (a AuthService) sendVerificationEmail(ctx context.Context, email string) (err error) {
+	token, err := entity.NewAuth().GenerateRandomToken()
+	if err != nil {
+		return
+	}
+
+	if err = a.redis.SetEmailVerificationToken(ctx, a.authCfg.VerifyTokenLifeTimeHour, token, email); err != nil {
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Welcome!\n\nOpen the link below within %d hours to verify your email address:\n%s?token=%s",
+		a.authCfg.VerifyTokenLifeTimeHour, a.authCfg.VerifyEmailURL, token,
+	)
+
+	if err = a.mail.Send(ctx, email, "Verify your email address", body); err != nil {
 		return
 	}
 
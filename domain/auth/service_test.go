@@ -4,27 +4,76 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/ecommerce/config"
-	"github.com/ecommerce/dto"
 	"github.com/ecommerce/entity"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var svc = AuthService{}
+var (
+	svc                  = AuthService{}
+	svcVerifyBeforeLogin = AuthService{}
+)
 
 type mockAuthRepository struct{}
 type mockRedisRepository struct{}
+type mockMailSender struct{}
+
+// Send implements mail.Sender.
+func (mockMailSender) Send(ctx context.Context, to string, subject string, body string) (err error) {
+	return SendMail()
+}
+
+// SetSession implements RedisRepository.
+func (mockRedisRepository) SetSession(ctx context.Context, timeLimit int, refreshToken string, session entity.Session) (err error) {
+	return SetSession()
+}
+
+// ConsumeRefreshToken implements RedisRepository.
+func (mockRedisRepository) ConsumeRefreshToken(ctx context.Context, refreshToken string) (session entity.Session, err error) {
+	return ConsumeRefreshToken()
+}
+
+// DeleteSession implements RedisRepository.
+func (mockRedisRepository) DeleteSession(ctx context.Context, id string, sessionID string) (err error) {
+	return DeleteSession()
+}
+
+// RevokeToken implements RedisRepository.
+func (mockRedisRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) (err error) {
+	return RevokeToken()
+}
+
+// RevokeUserTokens implements RedisRepository.
+func (mockRedisRepository) RevokeUserTokens(ctx context.Context, timeLimit int, id string) (err error) {
+	return RevokeUserTokens()
+}
 
-// Get implements RedisRepository.
-func (mockRedisRepository) Get(ctx context.Context, id string, email string) (token string, err error) {
-	return Get()
+// DeleteUserSessions implements RedisRepository.
+func (mockRedisRepository) DeleteUserSessions(ctx context.Context, id string) (err error) {
+	return DeleteUserSessions()
 }
 
-// Set implements RedisRepository.
-func (mockRedisRepository) Set(ctx context.Context, timeLimit int, token string, id string, email string) (err error) {
-	return Set()
+// SetPasswordResetToken implements RedisRepository.
+func (mockRedisRepository) SetPasswordResetToken(ctx context.Context, timeLimit int, token string, email string) (err error) {
+	return SetPasswordResetToken()
+}
+
+// ConsumePasswordResetToken implements RedisRepository.
+func (mockRedisRepository) ConsumePasswordResetToken(ctx context.Context, token string) (email string, err error) {
+	return ConsumePasswordResetToken()
+}
+
+// SetEmailVerificationToken implements RedisRepository.
+func (mockRedisRepository) SetEmailVerificationToken(ctx context.Context, timeLimit int, token string, email string) (err error) {
+	return SetEmailVerificationToken()
+}
+
+// ConsumeEmailVerificationToken implements RedisRepository.
+func (mockRedisRepository) ConsumeEmailVerificationToken(ctx context.Context, token string) (email string, err error) {
+	return ConsumeEmailVerificationToken()
 }
 
 // Create implements Repository.
@@ -37,9 +86,14 @@ func (mockAuthRepository) GetByEmail(ctx context.Context, email string) (user en
 	return GetByEmail()
 }
 
-// UpdateRole implements Repository.
-func (mockAuthRepository) UpdateRole(ctx context.Context, id string) (err error) {
-	return nil
+// VerifyEmail implements Repository.
+func (mockAuthRepository) VerifyEmail(ctx context.Context, id string) (err error) {
+	return VerifyEmail()
+}
+
+// UpdatePassword implements Repository.
+func (mockAuthRepository) UpdatePassword(ctx context.Context, id string, password string) (err error) {
+	return UpdatePassword()
 }
 
 func EncryptPassword(password string) (result string, err error) {
@@ -53,22 +107,45 @@ func EncryptPassword(password string) (result string, err error) {
 }
 
 var (
-	Get        func() (token string, err error)
-	Set        func() (err error)
-	Create     func() (err error)
-	GetByEmail func() (user entity.Auth, err error)
-	UpdateRole func() (err error)
+	SetSession                    func() (err error)
+	ConsumeRefreshToken           func() (session entity.Session, err error)
+	DeleteSession                 func() (err error)
+	RevokeToken                   func() (err error)
+	RevokeUserTokens              func() (err error)
+	DeleteUserSessions            func() (err error)
+	SetPasswordResetToken         func() (err error)
+	ConsumePasswordResetToken     func() (email string, err error)
+	SetEmailVerificationToken     func() (err error)
+	ConsumeEmailVerificationToken func() (email string, err error)
+	SendMail                      func() (err error)
+	Create                        func() (err error)
+	GetByEmail                    func() (user entity.Auth, err error)
+	UpdatePassword                func() (err error)
+	VerifyEmail                   func() (err error)
+	verifiedAt                    = "2023-12-11T09:00:00Z"
 )
 
 func init() {
 	mock := mockAuthRepository{}
 	mockRedis := mockRedisRepository{}
+	mockMail := mockMailSender{}
 	jwtConfig := config.JWT{
-		Secret:            "secret",
-		TokenLifeTimeHour: 7,
+		Secret:                   "secret",
+		TokenLifeTimeHour:        1,
+		RefreshTokenLifeTimeHour: 7,
 	}
+	authConfig := config.Auth{
+		ResetTokenLifeTimeMinute: 30,
+		ResetPasswordURL:         "http://localhost/reset-password",
+		VerifyTokenLifeTimeHour:  24,
+		VerifyEmailURL:           "http://localhost/v1/auth/verify",
+		VerificationPolicy:       entity.VerificationPolicyMerchant,
+	}
+
+	svc = NewAuthService(mock, mockRedis, mockMail, jwtConfig, authConfig)
 
-	svc = NewAuthService(mock, mockRedis, jwtConfig)
+	authConfig.VerificationPolicy = entity.VerificationPolicyLogin
+	svcVerifyBeforeLogin = NewAuthService(mock, mockRedis, mockMail, jwtConfig, authConfig)
 }
 
 func TestRegister(t *testing.T) {
@@ -100,6 +177,40 @@ func TestRegister(t *testing.T) {
 				Create = func() (err error) {
 					return nil
 				}
+
+				SetEmailVerificationToken = func() (err error) {
+					return nil
+				}
+
+				SendMail = func() (err error) {
+					return nil
+				}
+			},
+		},
+		{
+			title:       "register success: send verification email error is only logged",
+			expectedErr: nil,
+			request: entity.Auth{
+				ID:       "1",
+				Email:    "user2@gmail.com",
+				Password: "password",
+			},
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{}, nil
+				}
+
+				Create = func() (err error) {
+					return nil
+				}
+
+				SetEmailVerificationToken = func() (err error) {
+					return nil
+				}
+
+				SendMail = func() (err error) {
+					return errors.New("send mail error")
+				}
 			},
 		},
 		{
@@ -138,21 +249,18 @@ func TestRegister(t *testing.T) {
 
 func TestLogin(t *testing.T) {
 	type testCase struct {
-		title         string
-		expectedErr   error
-		expectedValue dto.LoginResponse
-		request       entity.Auth
-		before        func()
+		title        string
+		expectedErr  error
+		expectedRole string
+		request      entity.Auth
+		before       func()
 	}
 
 	var testCases = []testCase{
 		{
-			title:       "login success",
-			expectedErr: nil,
-			expectedValue: dto.LoginResponse{
-				AccessToken: "token",
-				Role:        "user",
-			},
+			title:        "login success",
+			expectedErr:  nil,
+			expectedRole: "user",
 			request: entity.Auth{
 				Email:    "user@gmail.com",
 				Password: "password",
@@ -169,19 +277,15 @@ func TestLogin(t *testing.T) {
 					}, nil
 				}
 
-				Get = func() (token string, err error) {
-					return "token", nil
-				}
-
-				Set = func() (err error) {
+				SetSession = func() (err error) {
 					return nil
 				}
 			},
 		},
 		{
-			title:         "login failed invalid email or password",
-			expectedErr:   entity.ErrInvalidEmailOrPassword,
-			expectedValue: dto.LoginResponse{},
+			title:        "login failed invalid email or password",
+			expectedErr:  entity.ErrInvalidEmailOrPassword,
+			expectedRole: "",
 			request: entity.Auth{
 				Email:    "user2@gmail.com",
 				Password: "password",
@@ -198,19 +302,15 @@ func TestLogin(t *testing.T) {
 					}, nil
 				}
 
-				Get = func() (token string, err error) {
-					return "", nil
-				}
-
-				Set = func() (err error) {
+				SetSession = func() (err error) {
 					return nil
 				}
 			},
 		},
 		{
-			title:         "login failed: get access token error",
-			expectedValue: dto.LoginResponse{},
-			expectedErr:   errors.New("get access token error"),
+			title:        "login failed: set session error",
+			expectedErr:  errors.New("set session error"),
+			expectedRole: "",
 			request: entity.Auth{
 				Email:    "user@gmail.com",
 				Password: "password",
@@ -227,19 +327,15 @@ func TestLogin(t *testing.T) {
 					}, nil
 				}
 
-				Get = func() (token string, err error) {
-					return "", errors.New("get access token error")
-				}
-
-				Set = func() (err error) {
-					return nil
+				SetSession = func() (err error) {
+					return errors.New("set session error")
 				}
 			},
 		},
 		{
-			title:         "login failed: internal server error",
-			expectedValue: dto.LoginResponse{},
-			expectedErr:   errors.New("internal server error"),
+			title:        "login failed: internal server error",
+			expectedErr:  errors.New("internal server error"),
+			expectedRole: "",
 			request: entity.Auth{
 				Email:    "user2@gmail.com",
 				Password: "password",
@@ -249,78 +345,561 @@ func TestLogin(t *testing.T) {
 					return entity.Auth{}, errors.New("internal server error")
 				}
 
-				Get = func() (token string, err error) {
-					return "", nil
+				SetSession = func() (err error) {
+					return nil
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			user, accessToken, refreshToken, err := svc.Login(context.Background(), test.request)
+			require.Equal(t, test.expectedErr, err)
+			require.Equal(t, test.expectedRole, user.Role)
+
+			if test.expectedErr == nil {
+				require.NotEmpty(t, accessToken)
+				require.NotEmpty(t, refreshToken)
+			}
+		})
+	}
+}
+
+func TestLoginVerificationPolicy(t *testing.T) {
+	password, _ := EncryptPassword("password")
+	request := entity.Auth{
+		Email:    "user@gmail.com",
+		Password: "password",
+	}
+
+	SetSession = func() (err error) {
+		return nil
+	}
+
+	t.Run("login failed: email not verified", func(t *testing.T) {
+		GetByEmail = func() (user entity.Auth, err error) {
+			return entity.Auth{
+				ID:       "1",
+				Email:    "user@gmail.com",
+				Role:     "user",
+				Password: password,
+			}, nil
+		}
+
+		_, _, _, err := svcVerifyBeforeLogin.Login(context.Background(), request)
+		require.Equal(t, entity.ErrEmailNotVerified, err)
+
+		_, _, _, err = svc.Login(context.Background(), request)
+		require.Nil(t, err)
+	})
+
+	t.Run("login success: email verified", func(t *testing.T) {
+		GetByEmail = func() (user entity.Auth, err error) {
+			return entity.Auth{
+				ID:              "1",
+				Email:           "user@gmail.com",
+				Role:            "user",
+				Password:        password,
+				EmailVerifiedAt: &verifiedAt,
+			}, nil
+		}
+
+		_, _, _, err := svcVerifyBeforeLogin.Login(context.Background(), request)
+		require.Nil(t, err)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	type testCase struct {
+		title        string
+		expectedErr  error
+		expectedRole string
+		before       func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:        "refresh success",
+			expectedErr:  nil,
+			expectedRole: "merchant",
+			before: func() {
+				ConsumeRefreshToken = func() (session entity.Session, err error) {
+					return entity.Session{
+						ID:        "1",
+						Email:     "user@gmail.com",
+						SessionID: "session",
+					}, nil
+				}
+
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:    "1",
+						Email: "user@gmail.com",
+						Role:  "merchant",
+					}, nil
 				}
 
-				Set = func() (err error) {
+				SetSession = func() (err error) {
 					return nil
 				}
 			},
 		},
+		{
+			title:        "refresh failed: refresh token already used or expired",
+			expectedErr:  entity.ErrInvalidRefreshToken,
+			expectedRole: "",
+			before: func() {
+				ConsumeRefreshToken = func() (session entity.Session, err error) {
+					return entity.Session{}, nil
+				}
+			},
+		},
+		{
+			title:        "refresh failed: account no longer exists",
+			expectedErr:  entity.ErrInvalidRefreshToken,
+			expectedRole: "",
+			before: func() {
+				ConsumeRefreshToken = func() (session entity.Session, err error) {
+					return entity.Session{
+						ID:        "1",
+						Email:     "user@gmail.com",
+						SessionID: "session",
+					}, nil
+				}
+
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{}, nil
+				}
+			},
+		},
+		{
+			title:        "refresh failed: internal server error",
+			expectedErr:  errors.New("internal server error"),
+			expectedRole: "",
+			before: func() {
+				ConsumeRefreshToken = func() (session entity.Session, err error) {
+					return entity.Session{}, errors.New("internal server error")
+				}
+			},
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.title, func(t *testing.T) {
 			test.before()
 
-			user, accessToken, err := svc.Login(context.Background(), test.request)
+			user, accessToken, refreshToken, err := svc.Refresh(context.Background(), "refresh-token")
 			require.Equal(t, test.expectedErr, err)
-			require.Equal(t, test.expectedValue.AccessToken, accessToken)
-			require.Equal(t, test.expectedValue.Role, user.Role)
+			require.Equal(t, test.expectedRole, user.Role)
+
+			if test.expectedErr == nil {
+				require.NotEmpty(t, accessToken)
+				require.NotEqual(t, "refresh-token", refreshToken)
+			}
 		})
 	}
 }
 
-func TestUpdateRole(t *testing.T) {
+func TestLogout(t *testing.T) {
 	type testCase struct {
 		title       string
 		expectedErr error
-		id          string
-		email       string
 		before      func()
 	}
 
 	var testCases = []testCase{
 		{
-			title:       "update role success",
+			title:       "logout success",
 			expectedErr: nil,
-			id:          "1",
-			email:       "user@gmail.com",
+			before: func() {
+				DeleteSession = func() (err error) {
+					return nil
+				}
+
+				RevokeToken = func() (err error) {
+					return nil
+				}
+			},
+		},
+		{
+			title:       "logout failed: revoke token error",
+			expectedErr: errors.New("revoke token error"),
+			before: func() {
+				DeleteSession = func() (err error) {
+					return nil
+				}
+
+				RevokeToken = func() (err error) {
+					return errors.New("revoke token error")
+				}
+			},
+		},
+		{
+			title:       "logout failed: internal server error",
+			expectedErr: errors.New("internal server error"),
+			before: func() {
+				DeleteSession = func() (err error) {
+					return errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			err := svc.Logout(context.Background(), "1", "session", "jti", time.Now().Add(time.Hour))
+			require.Equal(t, test.expectedErr, err)
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	type testCase struct {
+		title        string
+		expectedErr  error
+		expectedSent bool
+		before       func()
+	}
+
+	var sent chan error
+
+	knownUser := func() (user entity.Auth, err error) {
+		return entity.Auth{
+			ID:    "1",
+			Email: "user@gmail.com",
+		}, nil
+	}
+
+	var testCases = []testCase{
+		{
+			title:        "forgot password success",
+			expectedErr:  nil,
+			expectedSent: true,
+			before: func() {
+				GetByEmail = knownUser
+
+				SetPasswordResetToken = func() (err error) {
+					return nil
+				}
+
+				SendMail = func() (err error) {
+					sent <- nil
+					return nil
+				}
+			},
+		},
+		{
+			title:        "forgot password unknown email does not send mail",
+			expectedErr:  nil,
+			expectedSent: false,
 			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{}, nil
+				}
+
+				SendMail = func() (err error) {
+					sent <- nil
+					return nil
+				}
+			},
+		},
+		{
+			title:        "forgot password send mail error is not returned",
+			expectedErr:  nil,
+			expectedSent: true,
+			before: func() {
+				GetByEmail = knownUser
+
+				SetPasswordResetToken = func() (err error) {
+					return nil
+				}
+
+				SendMail = func() (err error) {
+					err = errors.New("send mail error")
+					sent <- err
+					return
+				}
+			},
+		},
+		{
+			title:        "forgot password failed: repository error",
+			expectedErr:  errors.New("internal server error"),
+			expectedSent: false,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{}, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			sent = make(chan error, 1)
+			test.before()
+
+			err := svc.ForgotPassword(context.Background(), "user@gmail.com")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedSent {
+				select {
+				case <-sent:
+				case <-time.After(time.Second):
+					t.Fatal("reset mail was not sent")
+				}
+			} else {
+				require.Len(t, sent, 0)
+			}
+		})
+	}
+
+	t.Run("send password reset returns the mail error", func(t *testing.T) {
+		SetPasswordResetToken = func() (err error) {
+			return nil
+		}
+		SendMail = func() (err error) {
+			return errors.New("send mail error")
+		}
+
+		err := svc.sendPasswordReset(context.Background(), "user@gmail.com")
+		require.Equal(t, errors.New("send mail error"), err)
+	})
+}
+
+func TestResendVerification(t *testing.T) {
+	type testCase struct {
+		title        string
+		expectedErr  error
+		expectedSent bool
+		before       func()
+	}
+
+	var sent bool
+	verifiedAt := "2023-12-11T09:00:00Z"
+
+	var testCases = []testCase{
+		{
+			title:        "resend verification success",
+			expectedErr:  nil,
+			expectedSent: true,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:    "1",
+						Email: "user@gmail.com",
+					}, nil
+				}
+
+				SetEmailVerificationToken = func() (err error) {
+					return nil
+				}
+
+				SendMail = func() (err error) {
+					sent = true
+					return nil
+				}
+			},
+		},
+		{
+			title:        "resend verification verified email does not send mail",
+			expectedErr:  nil,
+			expectedSent: false,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:              "1",
+						Email:           "user@gmail.com",
+						EmailVerifiedAt: &verifiedAt,
+					}, nil
+				}
+			},
+		},
+		{
+			title:        "resend verification unknown email does not send mail",
+			expectedErr:  nil,
+			expectedSent: false,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{}, nil
+				}
+			},
+		},
+		{
+			title:        "resend verification failed: send mail error",
+			expectedErr:  errors.New("send mail error"),
+			expectedSent: false,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:    "1",
+						Email: "user@gmail.com",
+					}, nil
+				}
+
+				SendMail = func() (err error) {
+					return errors.New("send mail error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			sent = false
+			test.before()
+
+			err := svc.ResendVerification(context.Background(), "user@gmail.com")
+			require.Equal(t, test.expectedErr, err)
+			require.Equal(t, test.expectedSent, sent)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	type testCase struct {
+		title           string
+		expectedErr     error
+		expectedRevoked bool
+		before          func()
+	}
+
+	var revoked bool
+
+	var testCases = []testCase{
+		{
+			title:           "reset password success invalidates sessions",
+			expectedErr:     nil,
+			expectedRevoked: true,
+			before: func() {
+				ConsumePasswordResetToken = func() (email string, err error) {
+					return "user@gmail.com", nil
+				}
+
 				GetByEmail = func() (user entity.Auth, err error) {
 					return entity.Auth{
 						ID:    "1",
 						Email: "user@gmail.com",
-						Role:  "user",
 					}, nil
 				}
+
+				UpdatePassword = func() (err error) {
+					return nil
+				}
+
+				DeleteUserSessions = func() (err error) {
+					return nil
+				}
+
+				RevokeUserTokens = func() (err error) {
+					revoked = true
+					return nil
+				}
+			},
+		},
+		{
+			title:           "reset password failed: token already used or expired",
+			expectedErr:     entity.ErrInvalidResetToken,
+			expectedRevoked: false,
+			before: func() {
+				ConsumePasswordResetToken = func() (email string, err error) {
+					return "", nil
+				}
 			},
 		},
 		{
+			title:           "reset password failed: update password error",
+			expectedErr:     errors.New("internal server error"),
+			expectedRevoked: false,
+			before: func() {
+				ConsumePasswordResetToken = func() (email string, err error) {
+					return "user@gmail.com", nil
+				}
+
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:    "1",
+						Email: "user@gmail.com",
+					}, nil
+				}
+
+				UpdatePassword = func() (err error) {
+					return errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			revoked = false
+			test.before()
+
+			err := svc.ResetPassword(context.Background(), "token", entity.Auth{Password: "password"})
+			require.Equal(t, test.expectedErr, err)
+			require.Equal(t, test.expectedRevoked, revoked)
+		})
+	}
+}
 
-			title:       "update role failed: invalid role",
-			expectedErr: entity.ErrUserAlreadyMerchant,
-			id:          "1",
-			email:       "user@gmail.com",
+func TestVerifyEmail(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "verify email success",
+			expectedErr: nil,
 			before: func() {
+				ConsumeEmailVerificationToken = func() (email string, err error) {
+					return "user@gmail.com", nil
+				}
+
 				GetByEmail = func() (user entity.Auth, err error) {
 					return entity.Auth{
 						ID:    "1",
 						Email: "user@gmail.com",
-						Role:  "merchant",
 					}, nil
 				}
+
+				VerifyEmail = func() (err error) {
+					return nil
+				}
 			},
 		},
 		{
-			title:       "update role failed: internal server error",
+			title:       "verify email failed: token already used or expired",
+			expectedErr: entity.ErrInvalidVerifyToken,
+			before: func() {
+				ConsumeEmailVerificationToken = func() (email string, err error) {
+					return "", nil
+				}
+			},
+		},
+		{
+			title:       "verify email failed: internal server error",
 			expectedErr: errors.New("internal server error"),
-			id:          "1",
-			email:       "user@gmail.com",
 			before: func() {
+				ConsumeEmailVerificationToken = func() (email string, err error) {
+					return "user@gmail.com", nil
+				}
+
 				GetByEmail = func() (user entity.Auth, err error) {
-					return entity.Auth{}, errors.New("internal server error")
+					return entity.Auth{
+						ID:    "1",
+						Email: "user@gmail.com",
+					}, nil
+				}
+
+				VerifyEmail = func() (err error) {
+					return errors.New("internal server error")
 				}
 			},
 		},
@@ -330,7 +909,7 @@ func TestUpdateRole(t *testing.T) {
 		t.Run(test.title, func(t *testing.T) {
 			test.before()
 
-			err := svc.UpdateRole(context.Background(), test.email)
+			err := svc.VerifyEmail(context.Background(), "token")
 			require.Equal(t, test.expectedErr, err)
 		})
 	}
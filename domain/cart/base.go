@@ -0,0 +1,32 @@
+package cart
+
+import (
+	"github.com/ecommerce/config"
+	"github.com/ecommerce/domain/cart/repository"
+	productRepo "github.com/ecommerce/domain/product/repository"
+	"github.com/ecommerce/infra/middleware"
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type DB struct {
+	Dbx   *sqlx.DB
+	Redis *redis.Client
+	Cfg   config.Cart
+}
+
+func RegisterServiceCart(router fiber.Router, db DB) {
+	cartRepository := repository.NewCartRepository(db.Redis)
+	productRepository := productRepo.NewProductRepository(db.Dbx)
+	service := NewCartService(cartRepository, productRepository, db.Cfg)
+	handler := NewCartHandler(service)
+
+	var cartRouter = router.Group("/v1/cart")
+	{
+		cartRouter.Get("/", middleware.AuthMiddleware(), handler.GetCart)
+		cartRouter.Post("/items", middleware.AuthMiddleware(), handler.AddItem)
+		cartRouter.Patch("/items/:sku", middleware.AuthMiddleware(), handler.UpdateItem)
+		cartRouter.Delete("/items/:sku", middleware.AuthMiddleware(), handler.RemoveItem)
+	}
+}
@@ -0,0 +1,90 @@
+package cart
+
+import (
+	"fmt"
+
+	"github.com/ecommerce/dto"
+	"github.com/ecommerce/entity"
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CartHandler struct {
+	service Service
+}
+
+func NewCartHandler(service Service) CartHandler {
+	return CartHandler{
+		service: service,
+	}
+}
+
+func (ca CartHandler) GetCart(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+
+	response, err := ca.service.GetCart(c.UserContext(), id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "get cart success", response, fiber.StatusOK)
+}
+
+func (ca CartHandler) AddItem(c *fiber.Ctx) error {
+	var req dto.AddCartItemRequest
+	id := c.Locals("id").(string)
+
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	model, err := entity.NewCartItem().Validate(req)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, err := ca.service.AddItem(c.UserContext(), id, model)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "add cart item success", response, fiber.StatusOK)
+}
+
+func (ca CartHandler) UpdateItem(c *fiber.Ctx) error {
+	var req dto.UpdateCartItemRequest
+	id := c.Locals("id").(string)
+
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	model, err := entity.NewCartItem().ValidateUpdate(req, c.Params("sku"))
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, err := ca.service.UpdateItem(c.UserContext(), id, model)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "update cart item success", response, fiber.StatusOK)
+}
+
+func (ca CartHandler) RemoveItem(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+
+	response, err := ca.service.RemoveItem(c.UserContext(), id, c.Params("sku"))
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "remove cart item success", response, fiber.StatusOK)
+}
@@ -0,0 +1,19 @@
+package cart
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecommerce/entity"
+)
+
+type Repository interface {
+	GetItems(ctx context.Context, id string) (items []entity.CartItem, err error)
+	GetItem(ctx context.Context, id, sku string) (item entity.CartItem, err error)
+	SetItem(ctx context.Context, id string, item entity.CartItem, ttl time.Duration) (err error)
+	DeleteItem(ctx context.Context, id, sku string) (deleted bool, err error)
+}
+
+type ProductRepository interface {
+	GetSellableBySku(ctx context.Context, sku string) (product entity.Product, err error)
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ecommerce/entity"
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+type CartRepository struct {
+	redis *redis.Client
+}
+
+func NewCartRepository(redis *redis.Client) CartRepository {
+	return CartRepository{
+		redis: redis,
+	}
+}
+
+func cartKey(id string) string {
+	return fmt.Sprintf("cart:%s", id)
+}
+
+func (r CartRepository) GetItems(ctx context.Context, id string) (items []entity.CartItem, err error) {
+	values, err := r.redis.HGetAll(ctx, cartKey(id)).Result()
+	if err != nil {
+		return
+	}
+
+	items = []entity.CartItem{}
+	for _, value := range values {
+		var item entity.CartItem
+		if err = json.Unmarshal([]byte(value), &item); err != nil {
+			return
+		}
+
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].AddedAt == items[j].AddedAt {
+			return items[i].Sku < items[j].Sku
+		}
+		return items[i].AddedAt < items[j].AddedAt
+	})
+
+	return
+}
+
+func (r CartRepository) GetItem(ctx context.Context, id, sku string) (item entity.CartItem, err error) {
+	value, err := r.redis.HGet(ctx, cartKey(id), sku).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return entity.CartItem{}, nil
+		}
+		return
+	}
+
+	err = json.Unmarshal([]byte(value), &item)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r CartRepository) SetItem(ctx context.Context, id string, item entity.CartItem, ttl time.Duration) (err error) {
+	value, err := json.Marshal(item)
+	if err != nil {
+		return
+	}
+
+	pipe := r.redis.TxPipeline()
+	pipe.HSet(ctx, cartKey(id), item.Sku, value)
+	pipe.Expire(ctx, cartKey(id), ttl)
+
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
+
+func (r CartRepository) DeleteItem(ctx context.Context, id, sku string) (deleted bool, err error) {
+	total, err := r.redis.HDel(ctx, cartKey(id), sku).Result()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return total > 0, nil
+}
+
+func (r CartRepository) DeleteItems(ctx context.Context, id string, skus []string) (err error) {
+	if len(skus) == 0 {
+		return
+	}
+
+	err = r.redis.HDel(ctx, cartKey(id), skus...).Err()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,160 @@
+package cart
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ecommerce/config"
+	"github.com/ecommerce/dto"
+	"github.com/ecommerce/entity"
+)
+
+const defaultCartExpire = 30 * 24 * time.Hour
+
+type Service interface {
+	GetCart(ctx context.Context, id string) (response dto.GetCartResponse, err error)
+	AddItem(ctx context.Context, id string, req entity.CartItem) (response dto.GetCartResponse, err error)
+	UpdateItem(ctx context.Context, id string, req entity.CartItem) (response dto.GetCartResponse, err error)
+	RemoveItem(ctx context.Context, id, sku string) (response dto.GetCartResponse, err error)
+}
+
+type CartService struct {
+	repository        Repository
+	productRepository ProductRepository
+	ttl               time.Duration
+}
+
+func NewCartService(repository Repository, productRepository ProductRepository, cfg config.Cart) CartService {
+	ttl := time.Duration(cfg.ExpireDay) * 24 * time.Hour
+	if ttl <= 0 {
+		ttl = defaultCartExpire
+	}
+
+	return CartService{
+		repository:        repository,
+		productRepository: productRepository,
+		ttl:               ttl,
+	}
+}
+
+func (c CartService) GetCart(ctx context.Context, id string) (response dto.GetCartResponse, err error) {
+	items, err := c.repository.GetItems(ctx, id)
+	if err != nil {
+		return
+	}
+
+	products := map[string]entity.Product{}
+	for _, item := range items {
+		product, err := c.productRepository.GetSellableBySku(ctx, item.Sku)
+		if err != nil {
+			if sql.ErrNoRows == err {
+				continue
+			}
+			return response, err
+		}
+
+		products[item.Sku] = product
+	}
+
+	response = entity.NewCartItem().CartResponse(items, products)
+
+	return
+}
+
+func (c CartService) AddItem(ctx context.Context, id string, req entity.CartItem) (response dto.GetCartResponse, err error) {
+	product, err := c.getProduct(ctx, req.Sku)
+	if err != nil {
+		return
+	}
+
+	item, err := c.repository.GetItem(ctx, id, req.Sku)
+	if err != nil {
+		return
+	}
+
+	if item.Sku == "" {
+		items, err := c.repository.GetItems(ctx, id)
+		if err != nil {
+			return response, err
+		}
+
+		if len(items) >= entity.MaxCartItems {
+			return response, entity.ErrCartIsFull
+		}
+
+		item = req
+		item.Quantity = 0
+	}
+
+	item.Quantity += req.Quantity
+	if item.Quantity > entity.MaxCartQuantity {
+		return response, entity.ErrCartQuantityIsInvalid
+	}
+
+	return c.setItem(ctx, id, item, product)
+}
+
+func (c CartService) UpdateItem(ctx context.Context, id string, req entity.CartItem) (response dto.GetCartResponse, err error) {
+	item, err := c.repository.GetItem(ctx, id, req.Sku)
+	if err != nil {
+		return
+	}
+
+	if item.Sku == "" {
+		return response, entity.ErrCartItemNotFound
+	}
+
+	product, err := c.getProduct(ctx, req.Sku)
+	if err != nil {
+		return
+	}
+
+	item.Quantity = req.Quantity
+
+	return c.setItem(ctx, id, item, product)
+}
+
+func (c CartService) RemoveItem(ctx context.Context, id, sku string) (response dto.GetCartResponse, err error) {
+	deleted, err := c.repository.DeleteItem(ctx, id, sku)
+	if err != nil {
+		return
+	}
+
+	if !deleted {
+		return response, entity.ErrCartItemNotFound
+	}
+
+	return c.GetCart(ctx, id)
+}
+
+func (c CartService) setItem(ctx context.Context, id string, item entity.CartItem, product entity.Product) (response dto.GetCartResponse, err error) {
+	if product.VariantCount > 0 {
+		return response, entity.ErrCartVariantIsRequired
+	}
+
+	if product.Stock < item.Quantity {
+		return response, entity.ErrInsufficientStock
+	}
+
+	item.Price = product.Price
+
+	err = c.repository.SetItem(ctx, id, item, c.ttl)
+	if err != nil {
+		return
+	}
+
+	return c.GetCart(ctx, id)
+}
+
+func (c CartService) getProduct(ctx context.Context, sku string) (product entity.Product, err error) {
+	product, err = c.productRepository.GetSellableBySku(ctx, sku)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrProductNotFound
+		}
+		return
+	}
+
+	return
+}
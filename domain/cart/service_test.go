@@ -0,0 +1,287 @@
+package cart
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/config"
+	"github.com/ecommerce/entity"
+	"github.com/stretchr/testify/require"
+)
+
+var svc = CartService{}
+
+type mockCartRepository struct{}
+type mockProductRepository struct{}
+
+// GetItems implements Repository.
+func (mockCartRepository) GetItems(ctx context.Context, id string) (items []entity.CartItem, err error) {
+	return GetCartItems()
+}
+
+// GetItem implements Repository.
+func (mockCartRepository) GetItem(ctx context.Context, id string, sku string) (item entity.CartItem, err error) {
+	return GetCartItem(sku)
+}
+
+// SetItem implements Repository.
+func (mockCartRepository) SetItem(ctx context.Context, id string, item entity.CartItem, ttl time.Duration) (err error) {
+	return SetCartItem(item)
+}
+
+// DeleteItem implements Repository.
+func (mockCartRepository) DeleteItem(ctx context.Context, id string, sku string) (deleted bool, err error) {
+	return DeleteCartItem(sku)
+}
+
+// GetSellableBySku implements ProductRepository.
+func (mockProductRepository) GetSellableBySku(ctx context.Context, sku string) (product entity.Product, err error) {
+	return GetProductBySku(sku)
+}
+
+var (
+	GetCartItems    func() (items []entity.CartItem, err error)
+	GetCartItem     func(sku string) (item entity.CartItem, err error)
+	SetCartItem     func(item entity.CartItem) (err error)
+	DeleteCartItem  func(sku string) (deleted bool, err error)
+	GetProductBySku func(sku string) (product entity.Product, err error)
+)
+
+func init() {
+	svc = NewCartService(mockCartRepository{}, mockProductRepository{}, config.Cart{})
+}
+
+func coffee(sku string) (entity.Product, error) {
+	if sku != "SKU-1" {
+		return entity.Product{}, sql.ErrNoRows
+	}
+
+	return entity.Product{ID: 1, Sku: "SKU-1", Name: "Kopi", Price: 12000, Stock: 5, MerchantId: 2, MerchantName: "Toko Maju"}, nil
+}
+
+func TestGetCart(t *testing.T) {
+	t.Run("success : flags changed and missing items", func(t *testing.T) {
+		GetCartItems = func() (items []entity.CartItem, err error) {
+			return []entity.CartItem{
+				{Sku: "SKU-1", Quantity: 2, Price: 10000},
+				{Sku: "SKU-GONE", Quantity: 1, Price: 5000},
+			}, nil
+		}
+		GetProductBySku = coffee
+
+		response, err := svc.GetCart(context.Background(), "user-id")
+		require.NoError(t, err)
+		require.True(t, response.HasChanges)
+		require.Equal(t, 24000, response.TotalPrice)
+		require.Len(t, response.Merchants, 2)
+		require.True(t, response.Merchants[0].Items[0].PriceChanged)
+		require.True(t, response.Merchants[1].Items[0].Unavailable)
+	})
+
+	t.Run("err : repository error", func(t *testing.T) {
+		GetCartItems = func() (items []entity.CartItem, err error) {
+			return []entity.CartItem{{Sku: "SKU-1", Quantity: 1}}, nil
+		}
+		GetProductBySku = func(sku string) (product entity.Product, err error) {
+			return entity.Product{}, errors.New("internal server error")
+		}
+
+		_, err := svc.GetCart(context.Background(), "user-id")
+		require.Equal(t, errors.New("internal server error"), err)
+	})
+}
+
+func TestAddItem(t *testing.T) {
+	type testCase struct {
+		title       string
+		req         entity.CartItem
+		expectedErr error
+		before      func()
+	}
+
+	var saved entity.CartItem
+
+	var testCases = []testCase{
+		{
+			title:       "add item success: new item takes current price",
+			req:         entity.CartItem{Sku: "SKU-1", Quantity: 2},
+			expectedErr: nil,
+			before: func() {
+				GetProductBySku = coffee
+				GetCartItem = func(sku string) (item entity.CartItem, err error) {
+					return entity.CartItem{}, nil
+				}
+				GetCartItems = func() (items []entity.CartItem, err error) {
+					if saved.Sku == "" {
+						return []entity.CartItem{}, nil
+					}
+					return []entity.CartItem{saved}, nil
+				}
+				SetCartItem = func(item entity.CartItem) (err error) {
+					if item.Quantity != 2 || item.Price != 12000 {
+						return errors.New("unexpected item")
+					}
+					saved = item
+					return nil
+				}
+			},
+		},
+		{
+			title:       "add item success: quantity adds to existing item",
+			req:         entity.CartItem{Sku: "SKU-1", Quantity: 3},
+			expectedErr: nil,
+			before: func() {
+				GetCartItem = func(sku string) (item entity.CartItem, err error) {
+					return entity.CartItem{Sku: "SKU-1", Quantity: 2, Price: 10000}, nil
+				}
+				SetCartItem = func(item entity.CartItem) (err error) {
+					if item.Quantity != 5 || item.Price != 12000 {
+						return errors.New("unexpected item")
+					}
+					saved = item
+					return nil
+				}
+			},
+		},
+		{
+			title:       "add item failed: insufficient stock",
+			req:         entity.CartItem{Sku: "SKU-1", Quantity: 4},
+			expectedErr: entity.ErrInsufficientStock,
+			before: func() {
+				GetCartItem = func(sku string) (item entity.CartItem, err error) {
+					return entity.CartItem{Sku: "SKU-1", Quantity: 2}, nil
+				}
+			},
+		},
+		{
+			title:       "add item success: variant sku takes the variant price and stock",
+			req:         entity.CartItem{Sku: "SKU-1-M", Quantity: 3},
+			expectedErr: nil,
+			before: func() {
+				variantId := 7
+				GetProductBySku = func(sku string) (product entity.Product, err error) {
+					return entity.Product{ID: 1, Sku: "SKU-1-M", Name: "Kopi", Price: 15000, Stock: 3, MerchantId: 2, MerchantName: "Toko Maju", VariantId: &variantId}, nil
+				}
+				GetCartItem = func(sku string) (item entity.CartItem, err error) {
+					return entity.CartItem{}, nil
+				}
+				GetCartItems = func() (items []entity.CartItem, err error) {
+					return []entity.CartItem{saved}, nil
+				}
+				SetCartItem = func(item entity.CartItem) (err error) {
+					if item.Sku != "SKU-1-M" || item.Quantity != 3 || item.Price != 15000 {
+						return errors.New("unexpected item")
+					}
+					saved = item
+					return nil
+				}
+			},
+		},
+		{
+			title:       "add item failed: product sku of a product with variants",
+			req:         entity.CartItem{Sku: "SKU-1", Quantity: 1},
+			expectedErr: entity.ErrCartVariantIsRequired,
+			before: func() {
+				GetProductBySku = func(sku string) (product entity.Product, err error) {
+					product, err = coffee(sku)
+					product.VariantCount = 2
+					return
+				}
+			},
+		},
+		{
+			title:       "add item failed: product not found",
+			req:         entity.CartItem{Sku: "SKU-GONE", Quantity: 1},
+			expectedErr: entity.ErrProductNotFound,
+			before: func() {
+				GetProductBySku = coffee
+			},
+		},
+		{
+			title:       "add item failed: cart is full",
+			req:         entity.CartItem{Sku: "SKU-1", Quantity: 1},
+			expectedErr: entity.ErrCartIsFull,
+			before: func() {
+				GetCartItem = func(sku string) (item entity.CartItem, err error) {
+					return entity.CartItem{}, nil
+				}
+				GetCartItems = func() (items []entity.CartItem, err error) {
+					return make([]entity.CartItem, entity.MaxCartItems), nil
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, err := svc.AddItem(context.Background(), "user-id", test.req)
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.False(t, response.HasChanges)
+				require.Equal(t, saved.Quantity, response.TotalQuantity)
+				require.Equal(t, "Toko Maju", response.Merchants[0].MerchantName)
+			}
+		})
+	}
+}
+
+func TestUpdateItem(t *testing.T) {
+	t.Run("success : quantity is replaced", func(t *testing.T) {
+		GetProductBySku = coffee
+		GetCartItem = func(sku string) (item entity.CartItem, err error) {
+			return entity.CartItem{Sku: "SKU-1", Quantity: 4, Price: 12000}, nil
+		}
+		SetCartItem = func(item entity.CartItem) (err error) {
+			if item.Quantity != 1 {
+				return errors.New("unexpected item")
+			}
+			return nil
+		}
+		GetCartItems = func() (items []entity.CartItem, err error) {
+			return []entity.CartItem{{Sku: "SKU-1", Quantity: 1, Price: 12000}}, nil
+		}
+
+		response, err := svc.UpdateItem(context.Background(), "user-id", entity.CartItem{Sku: "SKU-1", Quantity: 1})
+		require.NoError(t, err)
+		require.Equal(t, 1, response.TotalQuantity)
+	})
+
+	t.Run("err : item not in cart", func(t *testing.T) {
+		GetCartItem = func(sku string) (item entity.CartItem, err error) {
+			return entity.CartItem{}, nil
+		}
+
+		_, err := svc.UpdateItem(context.Background(), "user-id", entity.CartItem{Sku: "SKU-1", Quantity: 1})
+		require.Equal(t, entity.ErrCartItemNotFound, err)
+	})
+}
+
+func TestRemoveItem(t *testing.T) {
+	t.Run("success : item removed", func(t *testing.T) {
+		DeleteCartItem = func(sku string) (deleted bool, err error) {
+			return true, nil
+		}
+		GetCartItems = func() (items []entity.CartItem, err error) {
+			return []entity.CartItem{}, nil
+		}
+
+		response, err := svc.RemoveItem(context.Background(), "user-id", "SKU-1")
+		require.NoError(t, err)
+		require.Len(t, response.Merchants, 0)
+	})
+
+	t.Run("err : item not in cart", func(t *testing.T) {
+		DeleteCartItem = func(sku string) (deleted bool, err error) {
+			return false, nil
+		}
+
+		_, err := svc.RemoveItem(context.Background(), "user-id", "SKU-1")
+		require.Equal(t, entity.ErrCartItemNotFound, err)
+	})
+}
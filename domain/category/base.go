@@ -2,6 +2,7 @@ package category
 
 import (
 	"github.com/ecommerce/domain/category/repository"
+	"github.com/ecommerce/entity"
 	"github.com/ecommerce/infra/middleware"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
@@ -18,7 +19,7 @@ func RegisterServiceCategory(router fiber.Router, db DB) {
 
 	var categoryRouter = router.Group("/v1/categories")
 	{
-		categoryRouter.Post("/", middleware.AuthMiddleware(), handler.CreateCategory)
+		categoryRouter.Post("/", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleAdmin), handler.CreateCategory)
 		categoryRouter.Get("/", middleware.AuthMiddleware(), handler.GetListCategory)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/ecommerce/config"
 	"github.com/ecommerce/dto"
@@ -185,6 +186,9 @@ func TestCreateCategoryHandler(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -309,6 +313,9 @@ func TestGetListCategoryHandler(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/ecommerce/config"
 	"github.com/ecommerce/infra/middleware"
@@ -89,6 +90,9 @@ func TestUploadFileHandler(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -1 +1,37 @@
 package merchant
+
+import (
+	"github.com/ecommerce/config"
+	authRepo "github.com/ecommerce/domain/auth/repository"
+	"github.com/ecommerce/domain/merchant/repository"
+	productRepo "github.com/ecommerce/domain/product/repository"
+	"github.com/ecommerce/entity"
+	"github.com/ecommerce/infra/middleware"
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type DB struct {
+	Dbx   *sqlx.DB
+	Redis *redis.Client
+	Cfg   config.JWT
+}
+
+func RegisterServiceMerchant(router fiber.Router, db DB) {
+	merchantRepository := repository.NewMerchantRepository(db.Dbx)
+	productRepository := productRepo.NewProductRepository(db.Dbx)
+	authRepository := authRepo.NewAuthRepository(db.Dbx)
+	redisRepository := authRepo.NewRedisRepository(db.Redis)
+	service := NewMerchantService(merchantRepository, productRepository, authRepository, redisRepository, db.Cfg)
+	handler := NewMerchantHandler(service)
+
+	var merchantRouter = router.Group("/v1/merchants")
+	{
+		merchantRouter.Post("/", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleUser), handler.RegisterMerchant)
+		merchantRouter.Get("/me", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.GetProfile)
+		merchantRouter.Put("/me", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.UpdateProfile)
+		merchantRouter.Get("/me/alerts", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.GetAlerts)
+		merchantRouter.Get("/:id", handler.GetStorefront)
+	}
+}
@@ -1 +1,141 @@
 package merchant
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ecommerce/dto"
+	"github.com/ecommerce/entity"
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type MerchantHandler struct {
+	service Service
+}
+
+func NewMerchantHandler(service Service) MerchantHandler {
+	return MerchantHandler{
+		service: service,
+	}
+}
+
+func (m MerchantHandler) RegisterMerchant(c *fiber.Ctx) error {
+	var req dto.CreateOrUpdateMerchantRequest
+
+	id := c.Locals("id").(string)
+	email := c.Locals("email").(string)
+
+	if err := c.BodyParser(&req); err != nil {
+		return WriteError(c, err)
+	}
+
+	model, err := entity.NewMerchant().Validate(req, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, err := m.service.RegisterMerchant(c.UserContext(), model, email)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "register merchant success", response, nil, fiber.StatusCreated)
+}
+
+func (m MerchantHandler) GetProfile(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+
+	response, err := m.service.GetProfile(c.UserContext(), id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "get merchant profile success", response, nil, fiber.StatusOK)
+}
+
+func (m MerchantHandler) UpdateProfile(c *fiber.Ctx) error {
+	var req dto.CreateOrUpdateMerchantRequest
+
+	id := c.Locals("id").(string)
+
+	if err := c.BodyParser(&req); err != nil {
+		return WriteError(c, err)
+	}
+
+	model, err := entity.NewMerchant().Validate(req, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, err := m.service.UpdateProfile(c.UserContext(), model, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "update merchant profile success", response, nil, fiber.StatusOK)
+}
+
+func (m MerchantHandler) GetStorefront(c *fiber.Ctx) error {
+	merchantId := c.Params("id")
+	queryParam := c.Query("query")
+
+	merchantIdValue, err := strconv.Atoi(merchantId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	limit := c.Query("limit", "10")
+	limitValue, err := strconv.Atoi(limit)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	page := c.Query("page", "1")
+	pageValue, err := strconv.Atoi(page)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, totalData, err := m.service.GetStorefront(c.UserContext(), merchantIdValue, queryParam, limitValue, pageValue)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if totalData == 0 {
+		return WriteSuccess(c, "get merchant storefront success", response, nil, fiber.StatusOK)
+	}
+
+	paginationResponse := dto.NewPaginationResponse(queryParam, limitValue, pageValue, totalData)
+
+	return WriteSuccess(c, "get merchant storefront success", response, paginationResponse, fiber.StatusOK)
+}
+
+func (m MerchantHandler) GetAlerts(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+
+	limitValue, pageValue, err := entity.NewNotification().ValidatePage(c.QueryInt("limit"), c.QueryInt("page"))
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, totalData, err := m.service.GetAlerts(c.UserContext(), id, limitValue, pageValue)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	paginationResponse := dto.NewPaginationResponse("", limitValue, pageValue, totalData)
+
+	return WriteSuccess(c, "get merchant alerts success", response, paginationResponse, fiber.StatusOK)
+}
@@ -8,4 +8,16 @@ import (
 
 type Repository interface {
 	GetByCreatedBy(ctx context.Context, createdBy string) (merchant entity.Merchant, err error)
+	GetById(ctx context.Context, id int) (merchant entity.Merchant, err error)
+	Create(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error)
+	Update(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error)
+	GetAlerts(ctx context.Context, merchantId, limit, page int) (notifications []entity.Notification, totalData int, err error)
+}
+
+type ProductRepository interface {
+	GetByMerchantId(ctx context.Context, queryParam string, limit, page, merchantId int) (products []entity.Product, totalData int, err error)
+}
+
+type TokenRepository interface {
+	RevokeUserTokens(ctx context.Context, timeLimit int, id string) (err error)
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ecommerce/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type MerchantRepository struct {
@@ -25,3 +26,111 @@ func (m MerchantRepository) GetByCreatedBy(ctx context.Context, createdBy string
 
 	return
 }
+
+func (m MerchantRepository) GetById(ctx context.Context, id int) (merchant entity.Merchant, err error) {
+	err = m.db.GetContext(ctx, &merchant, queryGetById, id)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// the role is upgraded first, it locks the owner so a concurrent request for
+// the same user finds the role already taken
+func (m MerchantRepository) Create(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error) {
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, queryUpdateRole, entity.RoleMerchant, merchant.CreatedBy, entity.RoleUser)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affected == 0 {
+		return response, entity.ErrUserAlreadyMerchant
+	}
+
+	stmt, err := tx.PrepareNamedContext(ctx, queryCreate)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	response = merchant
+	err = stmt.GetContext(ctx, &response, merchant)
+	if err != nil {
+		switch {
+		case isUniqueViolationOf(err, merchantOwnerKey):
+			err = entity.ErrUserAlreadyMerchant
+		case isUniqueViolation(err):
+			err = entity.ErrPhoneNumberAlreadyUsed
+		}
+		return entity.Merchant{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.Merchant{}, err
+	}
+	response.Role = entity.RoleMerchant
+
+	return response, nil
+}
+
+func (m MerchantRepository) Update(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error) {
+	stmt, err := m.db.PrepareNamedContext(ctx, queryUpdate)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	response = merchant
+	err = stmt.GetContext(ctx, &response, merchant)
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = entity.ErrPhoneNumberAlreadyUsed
+		}
+		return entity.Merchant{}, err
+	}
+
+	return
+}
+
+func (m MerchantRepository) GetAlerts(ctx context.Context, merchantId, limit, page int) (notifications []entity.Notification, totalData int, err error) {
+	notifications = []entity.Notification{}
+	err = m.db.SelectContext(ctx, &notifications, queryGetAlerts, merchantId, limit, (page-1)*limit)
+	if err != nil {
+		return
+	}
+
+	err = m.db.GetContext(ctx, &totalData, queryCountAlerts, merchantId)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func isUniqueViolation(err error) bool {
+	if err, ok := err.(*pq.Error); ok && err.Code == "23505" {
+		return true
+	}
+	return false
+}
+
+const merchantOwnerKey = "merchants_created_by_key"
+
+func isUniqueViolationOf(err error, constraint string) bool {
+	if err, ok := err.(*pq.Error); ok && err.Code == "23505" && err.Constraint == constraint {
+		return true
+	}
+	return false
+}
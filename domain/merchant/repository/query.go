@@ -10,9 +10,69 @@ const (
 		m.image_url,
 		m.city,
 		m.created_by,
+		m.created_at,
+		m.updated_at,
 		a.role
 	FROM merchants m
 	JOIN auth a ON a.id = m.created_by
 	WHERE m.created_by = $1
 	`
+
+	queryGetById = `
+	SELECT
+		m.id,
+		m.name,
+		m.phone_number,
+		m.address,
+		m.image_url,
+		m.city,
+		m.created_by,
+		m.created_at,
+		m.updated_at,
+		a.role
+	FROM merchants m
+	JOIN auth a ON a.id = m.created_by
+	WHERE m.id = $1 AND m.deleted_at IS NULL
+	`
+
+	queryCreate = `
+	INSERT INTO merchants (
+		name,
+		phone_number,
+		address,
+		image_url,
+		city,
+		created_by
+	) VALUES (:name, :phone_number, :address, :image_url, :city, :created_by)
+	RETURNING id, created_at
+	`
+
+	queryUpdate = `
+	UPDATE merchants SET
+		name = :name,
+		phone_number = :phone_number,
+		address = :address,
+		image_url = :image_url,
+		city = :city,
+		updated_by = :updated_by,
+		updated_at = NOW()
+	WHERE id = :id
+	RETURNING updated_at
+	`
+
+	queryUpdateRole = `
+	UPDATE auth SET role = $1, updated_at = NOW() WHERE id = $2 AND role = $3
+	`
+
+	queryGetAlerts = `
+	SELECT id, merchant_id, product_id, type, title, message, stock, threshold, read_at, created_at
+	FROM notifications
+	WHERE merchant_id = $1
+	ORDER BY id DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	queryCountAlerts = `
+	SELECT COUNT(id) as total_data FROM notifications WHERE merchant_id = $1
+	`
 )
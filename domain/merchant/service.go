@@ -1 +1,142 @@
 package merchant
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ecommerce/config"
+	"github.com/ecommerce/domain/auth"
+	"github.com/ecommerce/dto"
+	"github.com/ecommerce/entity"
+)
+
+type Service interface {
+	RegisterMerchant(ctx context.Context, req entity.Merchant, email string) (response dto.GetMerchantProfileResponse, err error)
+	GetProfile(ctx context.Context, token string) (response dto.GetMerchantProfileResponse, err error)
+	UpdateProfile(ctx context.Context, req entity.Merchant, token string) (response dto.GetMerchantProfileResponse, err error)
+	GetStorefront(ctx context.Context, id int, queryParam string, limit, page int) (response dto.GetMerchantStorefrontResponse, totalData int, err error)
+	GetAlerts(ctx context.Context, token string, limit, page int) (response []dto.GetMerchantAlertResponse, totalData int, err error)
+}
+
+type MerchantService struct {
+	repository        Repository
+	productRepository ProductRepository
+	authRepository    auth.Repository
+	tokenRepository   TokenRepository
+	cfg               config.JWT
+}
+
+func NewMerchantService(repository Repository, productRepository ProductRepository, authRepository auth.Repository, tokenRepository TokenRepository, cfg config.JWT) MerchantService {
+	return MerchantService{
+		repository:        repository,
+		productRepository: productRepository,
+		authRepository:    authRepository,
+		tokenRepository:   tokenRepository,
+		cfg:               cfg,
+	}
+}
+
+func (m MerchantService) RegisterMerchant(ctx context.Context, req entity.Merchant, email string) (response dto.GetMerchantProfileResponse, err error) {
+	user, err := m.authRepository.GetByEmail(ctx, email)
+	if err != nil {
+		return
+	}
+
+	if err = entity.NewAuth().ValidateUserRole(user.Role); err != nil {
+		return
+	}
+
+	if err = user.CheckEmailVerified(); err != nil {
+		return
+	}
+
+	merchant, err := m.repository.Create(ctx, req)
+	if err != nil {
+		return
+	}
+
+	if err = m.tokenRepository.RevokeUserTokens(ctx, m.cfg.TokenLifeTimeHour, user.ID); err != nil {
+		return
+	}
+
+	response = entity.NewMerchant().MerchantProfileResponse(merchant)
+
+	return
+}
+
+func (m MerchantService) GetProfile(ctx context.Context, token string) (response dto.GetMerchantProfileResponse, err error) {
+	merchant, err := m.repository.GetByCreatedBy(ctx, token)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrMerchantNotFound
+		}
+		return
+	}
+
+	response = entity.NewMerchant().MerchantProfileResponse(merchant)
+
+	return
+}
+
+func (m MerchantService) UpdateProfile(ctx context.Context, req entity.Merchant, token string) (response dto.GetMerchantProfileResponse, err error) {
+	merchant, err := m.repository.GetByCreatedBy(ctx, token)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrMerchantNotFound
+		}
+		return
+	}
+
+	req.ID = merchant.ID
+	req.CreatedBy = merchant.CreatedBy
+	req.CreatedAt = merchant.CreatedAt
+	req.Role = merchant.Role
+	req.UpdatedBy = &token
+
+	merchant, err = m.repository.Update(ctx, req)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewMerchant().MerchantProfileResponse(merchant)
+
+	return
+}
+
+func (m MerchantService) GetStorefront(ctx context.Context, id int, queryParam string, limit, page int) (response dto.GetMerchantStorefrontResponse, totalData int, err error) {
+	merchant, err := m.repository.GetById(ctx, id)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrMerchantNotFound
+		}
+		return
+	}
+
+	products, totalData, err := m.productRepository.GetByMerchantId(ctx, queryParam, limit, page, merchant.ID)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewMerchant().MerchantStorefrontResponse(merchant, products)
+
+	return
+}
+
+func (m MerchantService) GetAlerts(ctx context.Context, token string, limit, page int) (response []dto.GetMerchantAlertResponse, totalData int, err error) {
+	merchant, err := m.repository.GetByCreatedBy(ctx, token)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrMerchantNotFound
+		}
+		return
+	}
+
+	notifications, totalData, err := m.repository.GetAlerts(ctx, merchant.ID, limit, page)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewNotification().AlertResponse(notifications)
+
+	return
+}
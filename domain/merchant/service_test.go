@@ -0,0 +1,532 @@
+package merchant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ecommerce/config"
+	"github.com/ecommerce/entity"
+	"github.com/stretchr/testify/require"
+)
+
+var svc = MerchantService{}
+
+type mockMerchantRepository struct{}
+type mockProductRepository struct{}
+type mockAuthRepository struct{}
+type mockTokenRepository struct{}
+
+// GetByCreatedBy implements Repository.
+func (mockMerchantRepository) GetByCreatedBy(ctx context.Context, createdBy string) (merchant entity.Merchant, err error) {
+	return GetMerchantByCreatedBy()
+}
+
+// GetById implements Repository.
+func (mockMerchantRepository) GetById(ctx context.Context, id int) (merchant entity.Merchant, err error) {
+	return GetMerchantById()
+}
+
+// Create implements Repository.
+func (mockMerchantRepository) Create(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error) {
+	return CreateMerchant(merchant)
+}
+
+// Update implements Repository.
+func (mockMerchantRepository) Update(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error) {
+	return UpdateMerchant(merchant)
+}
+
+// GetAlerts implements Repository.
+func (mockMerchantRepository) GetAlerts(ctx context.Context, merchantId, limit, page int) (notifications []entity.Notification, totalData int, err error) {
+	return GetMerchantAlerts(merchantId)
+}
+
+// GetByMerchantId implements ProductRepository.
+func (mockProductRepository) GetByMerchantId(ctx context.Context, queryParam string, limit int, page int, merchantId int) (products []entity.Product, totalData int, err error) {
+	return GetProductByMerchantId(merchantId)
+}
+
+// Create implements auth.Repository.
+func (mockAuthRepository) Create(ctx context.Context, user entity.Auth) (err error) {
+	return nil
+}
+
+// GetByEmail implements auth.Repository.
+func (mockAuthRepository) GetByEmail(ctx context.Context, email string) (user entity.Auth, err error) {
+	return GetByEmail()
+}
+
+// UpdatePassword implements auth.Repository.
+func (mockAuthRepository) UpdatePassword(ctx context.Context, id string, password string) (err error) {
+	return nil
+}
+
+// VerifyEmail implements auth.Repository.
+func (mockAuthRepository) VerifyEmail(ctx context.Context, id string) (err error) {
+	return nil
+}
+
+// RevokeUserTokens implements TokenRepository.
+func (mockTokenRepository) RevokeUserTokens(ctx context.Context, timeLimit int, id string) (err error) {
+	return RevokeUserTokens()
+}
+
+var (
+	GetMerchantByCreatedBy func() (merchant entity.Merchant, err error)
+	GetMerchantById        func() (merchant entity.Merchant, err error)
+	GetProductByMerchantId func(merchantId int) (products []entity.Product, totalData int, err error)
+	CreateMerchant         func(merchant entity.Merchant) (response entity.Merchant, err error)
+	UpdateMerchant         func(merchant entity.Merchant) (response entity.Merchant, err error)
+	GetMerchantAlerts      func(merchantId int) (notifications []entity.Notification, totalData int, err error)
+	GetByEmail             func() (user entity.Auth, err error)
+	RevokeUserTokens       func() (err error)
+	verifiedAt             = "2023-12-11T09:00:00Z"
+)
+
+func init() {
+	svc = NewMerchantService(mockMerchantRepository{}, mockProductRepository{}, mockAuthRepository{}, mockTokenRepository{}, config.JWT{TokenLifeTimeHour: 1})
+}
+
+func TestRegisterMerchant(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var req = entity.Merchant{
+		Name:        "Toko Maju",
+		PhoneNumber: "081234567890",
+		Address:     "Jl. Merdeka No. 1",
+		City:        "Jakarta",
+		ImageUrl:    "https://example.com/logo.png",
+		CreatedBy:   "1",
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "register merchant success",
+			expectedErr: nil,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:              "1",
+						Email:           "user@gmail.com",
+						Role:            entity.RoleUser,
+						EmailVerifiedAt: &verifiedAt,
+					}, nil
+				}
+
+				CreateMerchant = func(merchant entity.Merchant) (response entity.Merchant, err error) {
+					merchant.ID = 1
+					merchant.Role = entity.RoleMerchant
+					merchant.CreatedAt = "2023-12-13T09:00:00Z"
+					return merchant, nil
+				}
+
+				RevokeUserTokens = func() (err error) {
+					return nil
+				}
+			},
+		},
+		{
+			title:       "register merchant failed: user already merchant",
+			expectedErr: entity.ErrUserAlreadyMerchant,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:              "1",
+						Email:           "user@gmail.com",
+						Role:            entity.RoleMerchant,
+						EmailVerifiedAt: &verifiedAt,
+					}, nil
+				}
+			},
+		},
+		{
+			title:       "register merchant failed: email not verified",
+			expectedErr: entity.ErrEmailNotVerified,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:    "1",
+						Email: "user@gmail.com",
+						Role:  entity.RoleUser,
+					}, nil
+				}
+			},
+		},
+		{
+			title:       "register merchant failed: phone number already used",
+			expectedErr: entity.ErrPhoneNumberAlreadyUsed,
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:              "1",
+						Email:           "user@gmail.com",
+						Role:            entity.RoleUser,
+						EmailVerifiedAt: &verifiedAt,
+					}, nil
+				}
+
+				CreateMerchant = func(merchant entity.Merchant) (response entity.Merchant, err error) {
+					return entity.Merchant{}, entity.ErrPhoneNumberAlreadyUsed
+				}
+			},
+		},
+		{
+			title:       "register merchant failed: concurrent request already onboarded the user",
+			expectedErr: entity.ErrUserAlreadyMerchant,
+			before: func() {
+				CreateMerchant = func(merchant entity.Merchant) (response entity.Merchant, err error) {
+					return entity.Merchant{}, entity.ErrUserAlreadyMerchant
+				}
+
+				RevokeUserTokens = func() (err error) {
+					return errors.New("unexpected revoke")
+				}
+			},
+		},
+		{
+			title:       "register merchant failed: revoke tokens error",
+			expectedErr: errors.New("revoke tokens error"),
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{
+						ID:              "1",
+						Email:           "user@gmail.com",
+						Role:            entity.RoleUser,
+						EmailVerifiedAt: &verifiedAt,
+					}, nil
+				}
+
+				CreateMerchant = func(merchant entity.Merchant) (response entity.Merchant, err error) {
+					return merchant, nil
+				}
+
+				RevokeUserTokens = func() (err error) {
+					return errors.New("revoke tokens error")
+				}
+			},
+		},
+		{
+			title:       "register merchant failed: internal server error",
+			expectedErr: errors.New("internal server error"),
+			before: func() {
+				GetByEmail = func() (user entity.Auth, err error) {
+					return entity.Auth{}, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, err := svc.RegisterMerchant(context.Background(), req, "user@gmail.com")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 1, response.ID)
+				require.Equal(t, req.Name, response.Name)
+				require.Equal(t, req.PhoneNumber, response.PhoneNumber)
+			}
+		})
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "get profile success",
+			expectedErr: nil,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{
+						ID:          1,
+						Name:        "Toko Maju",
+						PhoneNumber: "081234567890",
+						CreatedBy:   "1",
+					}, nil
+				}
+			},
+		},
+		{
+			title:       "get profile failed: merchant not found",
+			expectedErr: entity.ErrMerchantNotFound,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, sql.ErrNoRows
+				}
+			},
+		},
+		{
+			title:       "get profile failed: internal server error",
+			expectedErr: errors.New("internal server error"),
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, err := svc.GetProfile(context.Background(), "1")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 1, response.ID)
+				require.Equal(t, "Toko Maju", response.Name)
+			}
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var req = entity.Merchant{
+		Name:        "Toko Maju Jaya",
+		PhoneNumber: "081234567891",
+		Address:     "Jl. Merdeka No. 2",
+		City:        "Bandung",
+		ImageUrl:    "https://example.com/logo-new.png",
+		CreatedBy:   "1",
+	}
+
+	var updated entity.Merchant
+
+	var testCases = []testCase{
+		{
+			title:       "update profile success",
+			expectedErr: nil,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{
+						ID:        1,
+						Name:      "Toko Maju",
+						CreatedBy: "1",
+						CreatedAt: "2023-12-13T09:00:00Z",
+					}, nil
+				}
+
+				UpdateMerchant = func(merchant entity.Merchant) (response entity.Merchant, err error) {
+					updatedAt := "2023-12-14T09:00:00Z"
+					merchant.UpdatedAt = &updatedAt
+					updated = merchant
+					return merchant, nil
+				}
+			},
+		},
+		{
+			title:       "update profile failed: merchant not found",
+			expectedErr: entity.ErrMerchantNotFound,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, sql.ErrNoRows
+				}
+			},
+		},
+		{
+			title:       "update profile failed: phone number already used",
+			expectedErr: entity.ErrPhoneNumberAlreadyUsed,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+				}
+
+				UpdateMerchant = func(merchant entity.Merchant) (response entity.Merchant, err error) {
+					return entity.Merchant{}, entity.ErrPhoneNumberAlreadyUsed
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, err := svc.UpdateProfile(context.Background(), req, "1")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 1, updated.ID)
+				require.Equal(t, "1", *updated.UpdatedBy)
+				require.Equal(t, "2023-12-13T09:00:00Z", response.CreatedAt)
+				require.Equal(t, "2023-12-14T09:00:00Z", response.UpdatedAt)
+				require.Equal(t, req.Name, response.Name)
+			}
+		})
+	}
+}
+
+func TestGetStorefront(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "get storefront success",
+			expectedErr: nil,
+			before: func() {
+				GetMerchantById = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{
+						ID:          2,
+						Name:        "Toko Maju",
+						PhoneNumber: "081234567890",
+						Address:     "Jl. Merdeka No. 1",
+						City:        "Jakarta",
+						ImageUrl:    "https://example.com/logo.png",
+					}, nil
+				}
+
+				GetProductByMerchantId = func(merchantId int) (products []entity.Product, totalData int, err error) {
+					if merchantId != 2 {
+						return nil, 0, errors.New("unexpected merchant id")
+					}
+
+					return []entity.Product{
+						{ID: 1, Sku: "SKU-1", Name: "Kopi", Price: 10000, Stock: 5},
+					}, 1, nil
+				}
+			},
+		},
+		{
+			title:       "get storefront failed: merchant not found",
+			expectedErr: entity.ErrMerchantNotFound,
+			before: func() {
+				GetMerchantById = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, sql.ErrNoRows
+				}
+			},
+		},
+		{
+			title:       "get storefront failed: internal server error",
+			expectedErr: errors.New("internal server error"),
+			before: func() {
+				GetMerchantById = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 2}, nil
+				}
+
+				GetProductByMerchantId = func(merchantId int) (products []entity.Product, totalData int, err error) {
+					return nil, 0, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, totalData, err := svc.GetStorefront(context.Background(), 2, "", 10, 1)
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 1, totalData)
+				require.Equal(t, 2, response.ID)
+				require.Equal(t, "Jl. Merdeka No. 1", response.Address)
+				require.Equal(t, "https://example.com/logo.png", response.ImageUrl)
+				require.Len(t, response.Products, 1)
+				require.Equal(t, "SKU-1", response.Products[0].Sku)
+			}
+		})
+	}
+}
+
+func TestGetAlerts(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "get alerts success",
+			expectedErr: nil,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 2}, nil
+				}
+
+				GetMerchantAlerts = func(merchantId int) (notifications []entity.Notification, totalData int, err error) {
+					if merchantId != 2 {
+						return nil, 0, errors.New("unexpected merchant id")
+					}
+
+					notification := entity.NewNotification().LowStock(entity.Notification{
+						ID:          7,
+						MerchantId:  2,
+						ProductId:   1,
+						ProductName: "Kopi",
+						Stock:       2,
+						Threshold:   5,
+					})
+
+					return []entity.Notification{notification}, 1, nil
+				}
+			},
+		},
+		{
+			title:       "get alerts failed: merchant not found",
+			expectedErr: entity.ErrMerchantNotFound,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, sql.ErrNoRows
+				}
+			},
+		},
+		{
+			title:       "get alerts failed: internal server error",
+			expectedErr: errors.New("internal server error"),
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 2}, nil
+				}
+
+				GetMerchantAlerts = func(merchantId int) (notifications []entity.Notification, totalData int, err error) {
+					return nil, 0, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, totalData, err := svc.GetAlerts(context.Background(), "user-id", 10, 1)
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 1, totalData)
+				require.Len(t, response, 1)
+				require.Equal(t, 7, response[0].ID)
+				require.Equal(t, entity.NotificationLowStock, response[0].Type)
+				require.Equal(t, 2, response[0].Stock)
+				require.Equal(t, 5, response[0].Threshold)
+			}
+		})
+	}
+}
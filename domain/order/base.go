@@ -1 +1,46 @@
 package order
+
+import (
+	"context"
+
+	"github.com/ecommerce/config"
+	cartRepo "github.com/ecommerce/domain/cart/repository"
+	"github.com/ecommerce/domain/order/repository"
+	"github.com/ecommerce/infra/middleware"
+	"github.com/ecommerce/infra/payment"
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type DB struct {
+	Dbx     *sqlx.DB
+	Redis   *redis.Client
+	Gateway payment.PaymentGateway
+}
+
+func RegisterServiceOrder(router fiber.Router, db DB) {
+	orderRepository := repository.NewOrderRepository(db.Dbx)
+	cartRepository := cartRepo.NewCartRepository(db.Redis)
+	service := NewOrderService(orderRepository, cartRepository, db.Gateway)
+	handler := NewOrderHandler(service)
+
+	var orderRouter = router.Group("/v1/orders")
+	{
+		orderRouter.Post("/checkout", middleware.AuthMiddleware(), handler.Checkout)
+		orderRouter.Patch("/:id/status", middleware.AuthMiddleware(), handler.UpdateStatus)
+		orderRouter.Get("/:id/history", middleware.AuthMiddleware(), handler.GetStatusHistory)
+	}
+
+	// the gateway signs its callbacks, there is no user token to check
+	var paymentRouter = router.Group("/v1/payments")
+	{
+		paymentRouter.Post("/webhook", handler.PaymentWebhook)
+	}
+}
+
+func RunExpiryJob(ctx context.Context, db DB, cfg config.Order) {
+	orderRepository := repository.NewOrderRepository(db.Dbx)
+	lockRepository := repository.NewLockRepository(db.Redis)
+	NewExpiryJob(orderRepository, lockRepository, db.Gateway, cfg).Run(ctx)
+}
@@ -0,0 +1,119 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/config"
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/ecommerce/infra/payment"
+)
+
+const (
+	defaultExpiryInterval = time.Minute
+	defaultPaymentWindow  = time.Hour
+	defaultPaymentGrace   = 5 * time.Minute
+	expiryBatchSize       = 100
+	expiryLockName        = "order_expiry"
+)
+
+type ExpiryJob struct {
+	repository ExpiryRepository
+	lock       LockRepository
+	gateway    payment.PaymentGateway
+	window     time.Duration
+	grace      time.Duration
+	interval   time.Duration
+}
+
+func NewExpiryJob(repository ExpiryRepository, lock LockRepository, gateway payment.PaymentGateway, cfg config.Order) ExpiryJob {
+	interval := time.Duration(cfg.ExpiryIntervalMinute) * time.Minute
+	if interval <= 0 {
+		interval = defaultExpiryInterval
+	}
+
+	window := time.Duration(cfg.PaymentWindowMinute) * time.Minute
+	if window <= 0 {
+		window = defaultPaymentWindow
+	}
+
+	grace := time.Duration(cfg.PaymentGraceMinute) * time.Minute
+	if grace <= 0 {
+		grace = defaultPaymentGrace
+	}
+
+	return ExpiryJob{
+		repository: repository,
+		lock:       lock,
+		gateway:    gateway,
+		window:     window,
+		grace:      grace,
+		interval:   interval,
+	}
+}
+
+func (e ExpiryJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.Expire(ctx); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e ExpiryJob) Expire(ctx context.Context) (total int, err error) {
+	token, ok, err := e.lock.AcquireLock(ctx, expiryLockName, e.interval)
+	if err != nil || !ok {
+		return
+	}
+
+	defer func() {
+		if err := e.lock.ReleaseLock(context.Background(), expiryLockName, token); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		}
+	}()
+
+	held := true
+	for held {
+		expired, err := e.repository.ExpireUnpaid(ctx, e.window, e.grace, expiryBatchSize)
+
+		// a round can outlast the lock ttl, a lost lock ends it after this batch
+		for _, order := range expired {
+			expireInvoice(ctx, e.gateway, order)
+			held = held && e.refreshLock(ctx, token)
+		}
+
+		total += len(expired)
+		if err != nil {
+			return total, err
+		}
+
+		if len(expired) < expiryBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelInfo, fmt.Sprintf("expired %d unpaid orders", total))
+	}
+
+	return
+}
+
+func (e ExpiryJob) refreshLock(ctx context.Context, token string) bool {
+	ok, err := e.lock.RefreshLock(ctx, expiryLockName, token, e.interval)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+	}
+
+	return ok
+}
@@ -0,0 +1,195 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/config"
+	"github.com/ecommerce/entity"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExpiryRepository struct{}
+type mockLockRepository struct{}
+
+// ExpireUnpaid implements ExpiryRepository.
+func (mockExpiryRepository) ExpireUnpaid(ctx context.Context, window, grace time.Duration, limit int) (response []entity.Order, err error) {
+	return ExpireUnpaid(window, grace, limit)
+}
+
+// AcquireLock implements LockRepository.
+func (mockLockRepository) AcquireLock(ctx context.Context, name string, ttl time.Duration) (token string, ok bool, err error) {
+	return AcquireLock(ttl)
+}
+
+// RefreshLock implements LockRepository.
+func (mockLockRepository) RefreshLock(ctx context.Context, name string, token string, ttl time.Duration) (ok bool, err error) {
+	return RefreshLock(token)
+}
+
+// ReleaseLock implements LockRepository.
+func (mockLockRepository) ReleaseLock(ctx context.Context, name string, token string) (err error) {
+	return ReleaseLock(token)
+}
+
+var (
+	ExpireUnpaid func(window, grace time.Duration, limit int) (response []entity.Order, err error)
+	AcquireLock  func(ttl time.Duration) (token string, ok bool, err error)
+	RefreshLock  func(token string) (ok bool, err error)
+	ReleaseLock  func(token string) (err error)
+)
+
+func TestExpiryJob(t *testing.T) {
+	t.Run("success : expire batches past the payment window", func(t *testing.T) {
+		var received []time.Duration
+		calls := 0
+		ExpireUnpaid = func(window, grace time.Duration, limit int) (response []entity.Order, err error) {
+			received = []time.Duration{window, grace}
+			calls++
+			if calls == 1 {
+				return make([]entity.Order, limit), nil
+			}
+			return []entity.Order{{ID: "order-1", TrxId: "inv-1", InvoiceUrl: "https://checkout.example.com/inv-1"}, {ID: "order-2"}}, nil
+		}
+
+		expired := []string{}
+		ExpireInvoice = func(id string) (err error) {
+			expired = append(expired, id)
+			return errors.New("gateway timeout")
+		}
+
+		var lockTTL time.Duration
+		AcquireLock = func(ttl time.Duration) (token string, ok bool, err error) {
+			lockTTL = ttl
+			return "token", true, nil
+		}
+
+		refreshed := 0
+		RefreshLock = func(token string) (ok bool, err error) {
+			refreshed++
+			return token == "token", nil
+		}
+
+		released := ""
+		ReleaseLock = func(token string) (err error) {
+			released = token
+			return nil
+		}
+
+		job := NewExpiryJob(mockExpiryRepository{}, mockLockRepository{}, mockGateway{}, config.Order{PaymentWindowMinute: 30})
+		total, err := job.Expire(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expiryBatchSize+2, total)
+		require.Equal(t, []time.Duration{30 * time.Minute, defaultPaymentGrace}, received)
+		require.Equal(t, defaultExpiryInterval, lockTTL)
+		require.Equal(t, "token", released)
+		require.Equal(t, []string{"inv-1"}, expired)
+		require.Equal(t, expiryBatchSize+2, refreshed)
+	})
+
+	t.Run("success : lost lock ends the round after the batch", func(t *testing.T) {
+		calls := 0
+		ExpireUnpaid = func(window, grace time.Duration, limit int) (response []entity.Order, err error) {
+			calls++
+			return make([]entity.Order, limit), nil
+		}
+
+		AcquireLock = func(ttl time.Duration) (token string, ok bool, err error) {
+			return "token", true, nil
+		}
+
+		refreshed := 0
+		RefreshLock = func(token string) (ok bool, err error) {
+			refreshed++
+			return refreshed < 3, nil
+		}
+
+		ReleaseLock = func(token string) (err error) {
+			return nil
+		}
+
+		job := NewExpiryJob(mockExpiryRepository{}, mockLockRepository{}, mockGateway{}, config.Order{})
+		total, err := job.Expire(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+		require.Equal(t, expiryBatchSize, total)
+		require.Equal(t, 3, refreshed)
+	})
+
+	t.Run("success : lock held elsewhere skips the round", func(t *testing.T) {
+		called := false
+		ExpireUnpaid = func(window, grace time.Duration, limit int) (response []entity.Order, err error) {
+			called = true
+			return nil, nil
+		}
+
+		AcquireLock = func(ttl time.Duration) (token string, ok bool, err error) {
+			return "", false, nil
+		}
+
+		job := NewExpiryJob(mockExpiryRepository{}, mockLockRepository{}, mockGateway{}, config.Order{})
+		total, err := job.Expire(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 0, total)
+		require.False(t, called)
+		require.Equal(t, defaultPaymentWindow, job.window)
+		require.Equal(t, defaultPaymentGrace, job.grace)
+	})
+
+	t.Run("err : repository error still releases the lock and closes expired invoices", func(t *testing.T) {
+		ExpireUnpaid = func(window, grace time.Duration, limit int) (response []entity.Order, err error) {
+			return []entity.Order{{ID: "order-1", TrxId: "inv-1", InvoiceUrl: "https://checkout.example.com/inv-1"}}, errors.New("internal server error")
+		}
+
+		expired := []string{}
+		ExpireInvoice = func(id string) (err error) {
+			expired = append(expired, id)
+			return nil
+		}
+
+		AcquireLock = func(ttl time.Duration) (token string, ok bool, err error) {
+			return "token", true, nil
+		}
+
+		released := ""
+		ReleaseLock = func(token string) (err error) {
+			released = token
+			return nil
+		}
+
+		job := NewExpiryJob(mockExpiryRepository{}, mockLockRepository{}, mockGateway{}, config.Order{ExpiryIntervalMinute: 5})
+		total, err := job.Expire(context.Background())
+		require.Equal(t, errors.New("internal server error"), err)
+		require.Equal(t, 1, total)
+		require.Equal(t, []string{"inv-1"}, expired)
+		require.Equal(t, "token", released)
+		require.Equal(t, 5*time.Minute, job.interval)
+	})
+
+	t.Run("err : lock error", func(t *testing.T) {
+		AcquireLock = func(ttl time.Duration) (token string, ok bool, err error) {
+			return "", false, errors.New("redis unavailable")
+		}
+
+		job := NewExpiryJob(mockExpiryRepository{}, mockLockRepository{}, mockGateway{}, config.Order{})
+		_, err := job.Expire(context.Background())
+		require.Equal(t, errors.New("redis unavailable"), err)
+	})
+
+	t.Run("success : run stops when context is done", func(t *testing.T) {
+		calls := 0
+		AcquireLock = func(ttl time.Duration) (token string, ok bool, err error) {
+			calls++
+			return "", false, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		job := NewExpiryJob(mockExpiryRepository{}, mockLockRepository{}, mockGateway{}, config.Order{})
+		job.Run(ctx)
+		require.Equal(t, 1, calls)
+	})
+}
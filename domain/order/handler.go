@@ -1 +1,95 @@
 package order
+
+import (
+	"fmt"
+
+	"github.com/ecommerce/dto"
+	"github.com/ecommerce/entity"
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/ecommerce/infra/payment"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderHandler struct {
+	service Service
+}
+
+func NewOrderHandler(service Service) OrderHandler {
+	return OrderHandler{
+		service: service,
+	}
+}
+
+func (o OrderHandler) Checkout(c *fiber.Ctx) error {
+	var req dto.CheckoutRequest
+	id := c.Locals("id").(string)
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return err
+		}
+	}
+
+	model, err := entity.NewOrder().ValidateCheckout(req, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	email, _ := c.Locals("email").(string)
+
+	response, err := o.service.Checkout(c.UserContext(), id, email, model)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "checkout success", response, fiber.StatusCreated)
+}
+
+func (o OrderHandler) UpdateStatus(c *fiber.Ctx) error {
+	var req dto.UpdateOrderStatusRequest
+	id := c.Locals("id").(string)
+	role, _ := c.Locals("role").(string)
+
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	model, err := entity.NewOrderStatusHistory().Validate(req, c.Params("id"), id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, err := o.service.UpdateStatus(c.UserContext(), id, role, model)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "update order status success", response, fiber.StatusOK)
+}
+
+func (o OrderHandler) GetStatusHistory(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+	role, _ := c.Locals("role").(string)
+
+	response, err := o.service.GetStatusHistory(c.UserContext(), id, role, c.Params("id"))
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "get order status history success", response, fiber.StatusOK)
+}
+
+func (o OrderHandler) PaymentWebhook(c *fiber.Ctx) error {
+	response, err := o.service.PaymentCallback(c.UserContext(), c.Get(payment.SignatureHeader), c.Body())
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "payment callback success", response, fiber.StatusOK)
+}
@@ -1 +1,33 @@
 package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecommerce/entity"
+)
+
+type Repository interface {
+	Checkout(ctx context.Context, order entity.Order) (response []entity.Order, err error)
+	GetById(ctx context.Context, id string) (order entity.Order, err error)
+	IsMerchantOrder(ctx context.Context, id, userId string) (ok bool, err error)
+	UpdateStatus(ctx context.Context, history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error)
+	GetHistory(ctx context.Context, id string) (histories []entity.OrderStatusHistory, err error)
+	UpdateInvoice(ctx context.Context, order entity.Order, expiresAt time.Time) (err error)
+	MarkPaidAfterClose(ctx context.Context, history entity.OrderStatusHistory) (paidAt *string, err error)
+}
+
+type CartRepository interface {
+	GetItems(ctx context.Context, id string) (items []entity.CartItem, err error)
+	DeleteItems(ctx context.Context, id string, skus []string) (err error)
+}
+
+type ExpiryRepository interface {
+	ExpireUnpaid(ctx context.Context, window, grace time.Duration, limit int) (response []entity.Order, err error)
+}
+
+type LockRepository interface {
+	AcquireLock(ctx context.Context, name string, ttl time.Duration) (token string, ok bool, err error)
+	RefreshLock(ctx context.Context, name, token string, ttl time.Duration) (ok bool, err error)
+	ReleaseLock(ctx context.Context, name, token string) (err error)
+}
@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type OrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderRepository(db *sqlx.DB) OrderRepository {
+	return OrderRepository{
+		db: db,
+	}
+}
+
+func (o OrderRepository) Checkout(ctx context.Context, order entity.Order) (response []entity.Order, err error) {
+	tx, err := o.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	skus := []string{}
+	for _, detail := range order.Details {
+		skus = append(skus, detail.Sku)
+	}
+
+	products := []entity.Product{}
+	err = tx.SelectContext(ctx, &products, queryLockProducts, pq.Array(skus))
+	if err != nil {
+		return
+	}
+
+	variants := []entity.Product{}
+	err = tx.SelectContext(ctx, &variants, queryLockVariants, pq.Array(skus))
+	if err != nil {
+		return
+	}
+	products = append(products, variants...)
+
+	bySku := map[string]entity.Product{}
+	for _, product := range products {
+		bySku[product.Sku] = product
+	}
+
+	for i, detail := range order.Details {
+		product, ok := bySku[detail.Sku]
+		if !ok {
+			return response, entity.ErrProductNotFound
+		}
+
+		order, err = entity.NewOrder().PriceDetail(order, i, product)
+		if err != nil {
+			return
+		}
+	}
+
+	orders := entity.NewOrder().SplitByMerchant(order)
+	for i := range orders {
+		orders[i], err = createOrder(ctx, tx, orders[i])
+		if err != nil {
+			return
+		}
+	}
+
+	return orders, tx.Commit()
+}
+
+func createOrder(ctx context.Context, tx *sqlx.Tx, order entity.Order) (response entity.Order, err error) {
+	for _, detail := range order.Details {
+		var stockAfter int
+		err = tx.GetContext(ctx, &stockAfter, queryDecrementStock, detail.ProductId, detail.Quantity, order.CreatedBy)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err = entity.ErrInsufficientStock
+			}
+			return
+		}
+
+		if detail.VariantId != nil {
+			var id int
+			err = tx.GetContext(ctx, &id, queryDecrementVariantStock, *detail.VariantId, detail.Quantity)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					err = entity.ErrInsufficientStock
+				}
+				return
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, queryInsertMovement, detail.ProductId, detail.VariantId, entity.MovementSale, -detail.Quantity,
+			stockAfter, fmt.Sprintf("order %s", order.TrxId), order.CreatedBy)
+		if err != nil {
+			return
+		}
+	}
+
+	err = tx.GetContext(ctx, &order.CreatedAt, queryCreateOrder, order.ID, order.UserId, order.MerchantId, order.TrxId,
+		order.TotalPrice, order.Status, order.InvoiceUrl, order.CreatedBy)
+	if err != nil {
+		return
+	}
+
+	for _, detail := range order.Details {
+		_, err = tx.NamedExecContext(ctx, queryCreateOrderDetail, detail)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertHistory, order.ID, nil, order.Status, order.CreatedBy, entity.ActorBuyer, "")
+	if err != nil {
+		return
+	}
+
+	return order, nil
+}
+
+func (o OrderRepository) GetById(ctx context.Context, id string) (order entity.Order, err error) {
+	err = o.db.GetContext(ctx, &order, queryGetById, id)
+	if err != nil {
+		return
+	}
+
+	order.Details = []entity.OrderDetail{}
+	err = o.db.SelectContext(ctx, &order.Details, queryGetDetails, id)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (o OrderRepository) IsMerchantOrder(ctx context.Context, id, userId string) (ok bool, err error) {
+	err = o.db.GetContext(ctx, &ok, queryIsMerchantOrder, id, userId)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (o OrderRepository) UpdateStatus(ctx context.Context, history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+	tx, err := o.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	response, err = updateStatus(ctx, tx, history)
+	if err != nil {
+		return
+	}
+
+	return response, tx.Commit()
+}
+
+func (o OrderRepository) MarkPaidAfterClose(ctx context.Context, history entity.OrderStatusHistory) (paidAt *string, err error) {
+	tx, err := o.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &paidAt, queryMarkPaidAfterClose, history.OrderId, history.ToStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertHistory, history.OrderId, history.FromStatus, history.ToStatus,
+		history.ActorId, history.Actor, history.Reason)
+	if err != nil {
+		return
+	}
+
+	return paidAt, tx.Commit()
+}
+
+func (o OrderRepository) ExpireUnpaid(ctx context.Context, window, grace time.Duration, limit int) (response []entity.Order, err error) {
+	ids := []string{}
+	err = o.db.SelectContext(ctx, &ids, queryGetUnpaidIds, entity.OrderStatusPendingPayment,
+		window.Seconds(), grace.Seconds(), limit)
+	if err != nil {
+		return
+	}
+
+	response = []entity.Order{}
+	for _, id := range ids {
+		order, ok, err := o.expireUnpaid(ctx, id)
+		if err != nil {
+			return response, err
+		}
+
+		if ok {
+			response = append(response, order)
+		}
+	}
+
+	return
+}
+
+func (o OrderRepository) expireUnpaid(ctx context.Context, id string) (order entity.Order, ok bool, err error) {
+	tx, err := o.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &order, queryLockUnpaid, id, entity.OrderStatusPendingPayment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// paid in the meantime, or being paid right now
+			err = nil
+		}
+		return
+	}
+
+	history, err := entity.NewOrderStatusHistory().Transition(order, entity.OrderStatusHistory{
+		ToStatus: entity.OrderStatusExpired,
+		Reason:   "payment window passed",
+	}, entity.ActorSystem)
+	if err != nil {
+		return
+	}
+
+	_, err = updateStatus(ctx, tx, history)
+	if err != nil {
+		return
+	}
+
+	order.Status = history.ToStatus
+
+	return order, true, tx.Commit()
+}
+
+func updateStatus(ctx context.Context, tx *sqlx.Tx, history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+	var trxId string
+	err = tx.GetContext(ctx, &trxId, queryUpdateStatus, history.OrderId, history.FromStatus, history.ToStatus, history.ActorId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = entity.ErrOrderStatusConflict
+		}
+		return
+	}
+
+	err = tx.GetContext(ctx, &response, queryInsertHistory, history.OrderId, history.FromStatus, history.ToStatus,
+		history.ActorId, history.Actor, history.Reason)
+	if err != nil {
+		return
+	}
+
+	if history.ReleasesStock() {
+		err = releaseStock(ctx, tx, history, trxId)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func releaseStock(ctx context.Context, tx *sqlx.Tx, history entity.OrderStatusHistory, trxId string) (err error) {
+	details := []entity.OrderDetail{}
+	err = tx.SelectContext(ctx, &details, queryGetDetails, history.OrderId)
+	if err != nil {
+		return
+	}
+
+	// same lock order as checkout, by product id and then variant id
+	sort.Slice(details, func(i, j int) bool {
+		if details[i].ProductId != details[j].ProductId {
+			return details[i].ProductId < details[j].ProductId
+		}
+		return variantId(details[i]) < variantId(details[j])
+	})
+
+	for _, detail := range details {
+		if detail.VariantId != nil {
+			ok, err := releaseVariantStock(ctx, tx, detail)
+			if err != nil {
+				return err
+			}
+
+			// a removed variant took its stock with it
+			if !ok {
+				continue
+			}
+		}
+
+		var stockAfter int
+		err = tx.GetContext(ctx, &stockAfter, queryReleaseStock, detail.ProductId, detail.Quantity)
+		if err != nil {
+			// a purged product has nothing left to give the stock back to
+			if err == sql.ErrNoRows {
+				err = nil
+				continue
+			}
+			return
+		}
+
+		createdBy := detail.CreatedBy
+		if history.ActorId != nil {
+			createdBy = *history.ActorId
+		}
+
+		_, err = tx.ExecContext(ctx, queryInsertMovement, detail.ProductId, detail.VariantId, entity.MovementReturn, detail.Quantity,
+			stockAfter, fmt.Sprintf("order %s %s", trxId, strings.ToLower(history.ToStatus)), createdBy)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func releaseVariantStock(ctx context.Context, tx *sqlx.Tx, detail entity.OrderDetail) (ok bool, err error) {
+	var id int
+	err = tx.GetContext(ctx, &id, queryLockProduct, detail.ProductId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+
+	err = tx.GetContext(ctx, &id, queryReleaseVariantStock, *detail.VariantId, detail.ProductId, detail.Quantity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+
+	return true, nil
+}
+
+func variantId(detail entity.OrderDetail) int {
+	if detail.VariantId == nil {
+		return 0
+	}
+
+	return *detail.VariantId
+}
+
+func (o OrderRepository) GetHistory(ctx context.Context, id string) (histories []entity.OrderStatusHistory, err error) {
+	histories = []entity.OrderStatusHistory{}
+	err = o.db.SelectContext(ctx, &histories, queryGetHistory, id)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (o OrderRepository) UpdateInvoice(ctx context.Context, order entity.Order, expiresAt time.Time) (err error) {
+	var expiresIn *float64
+	if !expiresAt.IsZero() {
+		seconds := time.Until(expiresAt).Seconds()
+		expiresIn = &seconds
+	}
+
+	_, err = o.db.ExecContext(ctx, queryUpdateInvoice, order.ID, order.TrxId, order.InvoiceUrl, expiresIn)
+	if err != nil {
+		return
+	}
+
+	return
+}
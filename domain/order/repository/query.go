@@ -0,0 +1,174 @@
+package repository
+
+const (
+	// rows are locked in id order so concurrent checkouts cannot deadlock
+	queryLockProducts = `
+	SELECT
+		p.id,
+		p.sku,
+		p.name,
+		p.price,
+		p.stock,
+		p.merchant_id,
+		(SELECT COUNT(v.id) FROM product_variants v WHERE v.product_id = p.id) as variant_count
+	FROM products p
+	WHERE p.deleted_at IS NULL
+		AND (p.sku = ANY($1) OR p.id IN (SELECT v.product_id FROM product_variants v WHERE v.sku = ANY($1)))
+	ORDER BY p.id
+	FOR UPDATE OF p
+	`
+
+	// variants are only locked once their products are, the order the stock
+	// ledger uses too
+	queryLockVariants = `
+	SELECT
+		p.id,
+		v.sku,
+		p.name,
+		COALESCE(v.price, p.price) as price,
+		v.stock,
+		p.merchant_id,
+		0 as variant_count,
+		v.id as variant_id
+	FROM product_variants v
+	JOIN products p ON p.id = v.product_id
+	WHERE v.sku = ANY($1) AND p.deleted_at IS NULL
+	ORDER BY v.id
+	FOR UPDATE OF v
+	`
+
+	// the guard keeps stock from going negative, no row means not enough stock
+	queryDecrementStock = `
+	UPDATE products SET
+		stock = stock - $2,
+		updated_by = $3,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1 AND stock >= $2
+	RETURNING stock
+	`
+
+	queryDecrementVariantStock = `
+	UPDATE product_variants SET
+		stock = stock - $2,
+		updated_at = NOW()
+	WHERE id = $1 AND stock >= $2
+	RETURNING id
+	`
+
+	queryInsertMovement = `
+	INSERT INTO inventory_movements (product_id, variant_id, type, quantity, stock_after, reason, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	queryCreateOrder = `
+	INSERT INTO orders (id, user_id, merchant_id, trx_id, total_price, status, invoice_url, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING created_at
+	`
+
+	queryCreateOrderDetail = `
+	INSERT INTO order_details (order_id, product_id, variant_id, sku, product_name, price, quantity, total_price_product, created_by)
+	VALUES (:order_id, :product_id, :variant_id, :sku, :product_name, :price, :quantity, :total_price_product, :created_by)
+	`
+
+	queryInsertHistory = `
+	INSERT INTO order_status_history (order_id, from_status, to_status, actor_id, actor, reason)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, order_id, from_status, to_status, actor_id, actor, reason, created_at
+	`
+
+	queryGetById = `
+	SELECT id, user_id, COALESCE(merchant_id, 0) AS merchant_id, trx_id, total_price, status, invoice_url, created_by, created_at, updated_at, paid_after_close_at, payment_expires_at
+	FROM orders
+	WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	queryGetDetails = `
+	SELECT id, order_id, product_id, variant_id, sku, product_name, price, quantity, total_price_product, created_by, created_at
+	FROM order_details
+	WHERE order_id = $1 AND deleted_at IS NULL
+	ORDER BY created_at, id
+	`
+
+	queryIsMerchantOrder = `
+	SELECT EXISTS (
+		SELECT 1
+		FROM orders o
+		JOIN merchants m ON m.id = o.merchant_id
+		WHERE o.id = $1 AND m.created_by = $2
+	)
+	`
+
+	// the status guard turns a concurrent move into no row instead of a lost update
+	queryUpdateStatus = `
+	UPDATE orders SET
+		status = $3,
+		updated_by = $4,
+		updated_at = NOW()
+	WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+	RETURNING trx_id
+	`
+
+	queryLockProduct = `
+	SELECT id FROM products WHERE id = $1 FOR UPDATE
+	`
+
+	queryReleaseVariantStock = `
+	UPDATE product_variants SET
+		stock = stock + $3,
+		updated_at = NOW()
+	WHERE id = $1 AND product_id = $2
+	RETURNING id
+	`
+
+	queryReleaseStock = `
+	UPDATE products SET
+		stock = stock + $2,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1
+	RETURNING stock
+	`
+
+	queryGetHistory = `
+	SELECT id, order_id, from_status, to_status, actor_id, actor, reason, created_at
+	FROM order_status_history
+	WHERE order_id = $1
+	ORDER BY id
+	`
+
+	queryUpdateInvoice = `
+	UPDATE orders SET
+		trx_id = $2,
+		invoice_url = $3,
+		payment_expires_at = NOW() + make_interval(secs => $4),
+		updated_at = NOW()
+	WHERE id = $1
+	`
+
+	queryMarkPaidAfterClose = `
+	UPDATE orders SET
+		paid_after_close_at = NOW(),
+		updated_at = NOW()
+	WHERE id = $1 AND status = $2 AND paid_after_close_at IS NULL AND deleted_at IS NULL
+	RETURNING paid_after_close_at
+	`
+
+	queryGetUnpaidIds = `
+	SELECT id
+	FROM orders
+	WHERE status = $1
+		AND COALESCE(payment_expires_at, created_at + make_interval(secs => $2)) < NOW() - make_interval(secs => $3)
+		AND deleted_at IS NULL
+	ORDER BY created_at
+	LIMIT $4
+	`
+
+	queryLockUnpaid = `
+	SELECT id, user_id, COALESCE(merchant_id, 0) AS merchant_id, trx_id, total_price, status, invoice_url, created_by, created_at, updated_at, paid_after_close_at, payment_expires_at
+	FROM orders
+	WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+	FOR UPDATE SKIP LOCKED
+	`
+)
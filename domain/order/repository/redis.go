@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// releaseLockScript deletes the lock only while it still holds our token, a
+// lock that expired and was taken by another process is left alone.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+var refreshLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+type LockRepository struct {
+	redis *redis.Client
+}
+
+func NewLockRepository(redis *redis.Client) LockRepository {
+	return LockRepository{
+		redis: redis,
+	}
+}
+
+func lockKey(name string) string {
+	return fmt.Sprintf("lock:%s", name)
+}
+
+func (r LockRepository) AcquireLock(ctx context.Context, name string, ttl time.Duration) (token string, ok bool, err error) {
+	token = uuid.New().String()
+	ok, err = r.redis.SetNX(ctx, lockKey(name), token, ttl).Result()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
+
+func (r LockRepository) RefreshLock(ctx context.Context, name, token string, ttl time.Duration) (ok bool, err error) {
+	refreshed, err := refreshLockScript.Run(ctx, r.redis, []string{lockKey(name)}, token, ttl.Milliseconds()).Int()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return refreshed == 1, nil
+}
+
+func (r LockRepository) ReleaseLock(ctx context.Context, name, token string) (err error) {
+	err = releaseLockScript.Run(ctx, r.redis, []string{lockKey(name)}, token).Err()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	return
+}
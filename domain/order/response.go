@@ -0,0 +1,105 @@
+package order
+
+import (
+	"net/http"
+
+	"github.com/ecommerce/entity"
+	"github.com/ecommerce/infra/payment"
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+func WriteError(c *fiber.Ctx, err error) error {
+	switch {
+	case err == entity.ErrCartSkuIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40001", nil)
+	case err == entity.ErrCartQuantityIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40002", nil)
+	case err == entity.ErrOrderItemsIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40003", nil)
+	case err == entity.ErrOrderItemIsDuplicate:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40004", nil)
+	case err == entity.ErrOrderTooManyItems:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40005", nil)
+	case err == entity.ErrCartVariantIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40006", nil)
+	case err == entity.ErrOrderStatusIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40007", nil)
+	case err == entity.ErrReasonIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40008", nil)
+	case err == payment.ErrCallbackIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40009", nil)
+	case err == entity.ErrPaymentMismatch:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40010", nil)
+	case err == payment.ErrSignatureIsInvalid:
+		return write(c, http.StatusUnauthorized, "unauthorized", err.Error(), "40101", nil)
+	case err == entity.ErrOrderTransitionForbidden:
+		return write(c, http.StatusForbidden, "forbidden", err.Error(), "40301", nil)
+	case err == entity.ErrProductNotFound:
+		return write(c, http.StatusNotFound, "not found", err.Error(), "40401", nil)
+	case err == entity.ErrOrderNotFound:
+		return write(c, http.StatusNotFound, "not found", err.Error(), "40402", nil)
+	case err == entity.ErrInsufficientStock:
+		return write(c, http.StatusConflict, "conflict", err.Error(), "40901", nil)
+	case err == entity.ErrOrderPriceChanged:
+		return write(c, http.StatusConflict, "conflict", err.Error(), "40902", nil)
+	case err == entity.ErrOrderTransitionIsInvalid:
+		return write(c, http.StatusConflict, "conflict", err.Error(), "40903", nil)
+	case err == entity.ErrOrderStatusIsFinal:
+		return write(c, http.StatusConflict, "conflict", err.Error(), "40904", nil)
+	case err == entity.ErrOrderStatusConflict:
+		return write(c, http.StatusConflict, "conflict", err.Error(), "40905", nil)
+	case err == entity.ErrPaymentUnavailable:
+		return write(c, http.StatusBadGateway, "bad gateway", err.Error(), "50201", nil)
+	default:
+		if iSSQLIntegrityConstraintViolation(err) {
+			return write(c, http.StatusInternalServerError, "internal server error", "error repository", "50001", nil)
+		}
+		return write(c, http.StatusInternalServerError, "internal server error", err.Error(), "99999", nil)
+	}
+}
+
+func WriteSuccess(c *fiber.Ctx, message string, payload interface{}, statusCode int) error {
+	resp := response{
+		Success: true,
+		Message: message,
+		Payload: payload,
+	}
+	c = c.Status(statusCode)
+	return c.JSON(resp)
+}
+
+type response struct {
+	Success   bool        `json:"success"`
+	Message   string      `json:"message"`
+	Payload   interface{} `json:"payload,omitempty"`
+	Error     *string     `json:"error,omitempty"`
+	ErrorCode *string     `json:"error_code,omitempty"`
+}
+
+func write(c *fiber.Ctx, statusCode int, message, errorMessage, errorCode string, payload interface{}) error {
+	c = c.Status(statusCode)
+	isSuccess := statusCode >= 200 && statusCode < 300
+
+	if isSuccess {
+		return c.JSON(response{
+			Success: true,
+			Message: message,
+			Payload: payload,
+		})
+	}
+
+	return c.JSON(response{
+		Success:   false,
+		Message:   message,
+		Error:     &errorMessage,
+		ErrorCode: &errorCode,
+	})
+}
+
+func iSSQLIntegrityConstraintViolation(err error) bool {
+	if err, ok := err.(*pq.Error); ok && err.Code == "42601" {
+		return true
+	}
+	return false
+}
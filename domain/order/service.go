@@ -1 +1,291 @@
 package order
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ecommerce/dto"
+	"github.com/ecommerce/entity"
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/ecommerce/infra/payment"
+)
+
+type Service interface {
+	Checkout(ctx context.Context, id, email string, req entity.Order) (response dto.CheckoutResponse, err error)
+	UpdateStatus(ctx context.Context, id, role string, req entity.OrderStatusHistory) (response dto.GetOrderResponse, err error)
+	GetStatusHistory(ctx context.Context, id, role, orderId string) (response []dto.OrderStatusHistoryResponse, err error)
+	PaymentCallback(ctx context.Context, signature string, body []byte) (response dto.GetOrderResponse, err error)
+}
+
+type OrderService struct {
+	repository     Repository
+	cartRepository CartRepository
+	gateway        payment.PaymentGateway
+}
+
+func NewOrderService(repository Repository, cartRepository CartRepository, gateway payment.PaymentGateway) OrderService {
+	return OrderService{
+		repository:     repository,
+		cartRepository: cartRepository,
+		gateway:        gateway,
+	}
+}
+
+func (o OrderService) Checkout(ctx context.Context, id, email string, req entity.Order) (response dto.CheckoutResponse, err error) {
+	if len(req.Details) == 0 {
+		items, err := o.cartRepository.GetItems(ctx, id)
+		if err != nil {
+			return response, err
+		}
+
+		req, err = entity.NewOrder().ValidateCart(items, id)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	orders, err := o.repository.Checkout(ctx, req)
+	if err != nil {
+		return
+	}
+
+	for i := range orders {
+		invoiced, err := o.createInvoice(ctx, orders[i], email)
+		if err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+
+			// the checkout is paid as a whole or not at all
+			for _, order := range orders {
+				o.cancel(ctx, order, "invoice could not be created")
+			}
+
+			return response, entity.ErrPaymentUnavailable
+		}
+
+		orders[i] = invoiced
+	}
+
+	if req.FromCart {
+		skus := []string{}
+		for _, detail := range req.Details {
+			skus = append(skus, detail.Sku)
+		}
+
+		if err := o.cartRepository.DeleteItems(ctx, id, skus); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		}
+	}
+
+	response = entity.NewOrder().CheckoutResponse(orders)
+
+	return
+}
+
+func (o OrderService) UpdateStatus(ctx context.Context, id, role string, req entity.OrderStatusHistory) (response dto.GetOrderResponse, err error) {
+	order, actor, err := o.getOrder(ctx, id, role, req.OrderId)
+	if err != nil {
+		return
+	}
+
+	history, err := entity.NewOrderStatusHistory().Transition(order, req, actor)
+	if err != nil {
+		return
+	}
+
+	_, err = o.repository.UpdateStatus(ctx, history)
+	if err != nil {
+		return
+	}
+
+	if history.ClosesInvoice() {
+		expireInvoice(ctx, o.gateway, order)
+	}
+
+	order.Status = history.ToStatus
+	response = entity.NewOrder().OrderResponse(order)
+
+	return
+}
+
+func (o OrderService) GetStatusHistory(ctx context.Context, id, role, orderId string) (response []dto.OrderStatusHistoryResponse, err error) {
+	_, _, err = o.getOrder(ctx, id, role, orderId)
+	if err != nil {
+		return
+	}
+
+	histories, err := o.repository.GetHistory(ctx, orderId)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewOrderStatusHistory().HistoryResponse(histories)
+
+	return
+}
+
+func (o OrderService) getOrder(ctx context.Context, id, role, orderId string) (order entity.Order, actor string, err error) {
+	order, err = o.repository.GetById(ctx, orderId)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrOrderNotFound
+		}
+		return
+	}
+
+	switch {
+	case role == entity.RoleAdmin:
+		return order, entity.ActorAdmin, nil
+	case order.UserId == id:
+		return order, entity.ActorBuyer, nil
+	}
+
+	ok, err := o.repository.IsMerchantOrder(ctx, orderId, id)
+	if err != nil {
+		return
+	}
+
+	if !ok {
+		return order, "", entity.ErrOrderNotFound
+	}
+
+	return order, entity.ActorMerchant, nil
+}
+
+func (o OrderService) createInvoice(ctx context.Context, order entity.Order, email string) (response entity.Order, err error) {
+	invoice, err := o.gateway.CreateInvoice(ctx, payment.Invoice{
+		ExternalId:  order.ID,
+		Amount:      order.TotalPrice,
+		PayerEmail:  email,
+		Description: fmt.Sprintf("Order %s", order.TrxId),
+	})
+	if err != nil {
+		return
+	}
+
+	order.TrxId = invoice.Id
+	order.InvoiceUrl = invoice.InvoiceUrl
+	err = o.repository.UpdateInvoice(ctx, order, invoice.ExpiresAt)
+	if err != nil {
+		return
+	}
+
+	return order, nil
+}
+
+func (o OrderService) cancel(ctx context.Context, order entity.Order, reason string) {
+	history, err := entity.NewOrderStatusHistory().Transition(order, entity.OrderStatusHistory{
+		ToStatus: entity.OrderStatusCancelled,
+		Reason:   reason,
+	}, entity.ActorSystem)
+	if err == nil {
+		_, err = o.repository.UpdateStatus(ctx, history)
+	}
+
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return
+	}
+
+	expireInvoice(ctx, o.gateway, order)
+}
+
+func expireInvoice(ctx context.Context, gateway payment.PaymentGateway, order entity.Order) {
+	if order.InvoiceUrl == "" {
+		return
+	}
+
+	if err := gateway.ExpireInvoice(ctx, order.TrxId); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+	}
+}
+
+func (o OrderService) PaymentCallback(ctx context.Context, signature string, body []byte) (response dto.GetOrderResponse, err error) {
+	callback, err := o.gateway.VerifyCallback(signature, body)
+	if err != nil {
+		return
+	}
+
+	order, err := o.repository.GetById(ctx, callback.ExternalId)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrOrderNotFound
+		}
+		return
+	}
+
+	switch {
+	case callback.IsPaid() && order.IsClosedUnpaid():
+		order, err = o.markPaidAfterClose(ctx, order, callback)
+		if err != nil {
+			return
+		}
+	case callback.IsPaid() && !order.IsPaid():
+		if callback.Amount != order.TotalPrice {
+			return response, entity.ErrPaymentMismatch
+		}
+
+		order, err = o.markPaid(ctx, order, callback)
+		if err != nil {
+			return
+		}
+	}
+
+	response = entity.NewOrder().OrderResponse(order)
+
+	return
+}
+
+func (o OrderService) markPaid(ctx context.Context, order entity.Order, callback payment.Callback) (response entity.Order, err error) {
+	history, err := entity.NewOrderStatusHistory().Transition(order, entity.OrderStatusHistory{
+		ToStatus: entity.OrderStatusPaid,
+		Reason:   fmt.Sprintf("invoice %s paid", callback.Id),
+	}, entity.ActorSystem)
+	if err != nil {
+		return
+	}
+
+	_, err = o.repository.UpdateStatus(ctx, history)
+	if err == entity.ErrOrderStatusConflict {
+		// a concurrent delivery of the same callback may have won the race, or
+		// the order expired just before
+		order, err = o.repository.GetById(ctx, order.ID)
+		if err == nil && order.IsClosedUnpaid() {
+			return o.markPaidAfterClose(ctx, order, callback)
+		}
+		if err == nil && !order.IsPaid() {
+			err = entity.ErrOrderStatusConflict
+		}
+		return order, err
+	}
+
+	if err != nil {
+		return
+	}
+
+	order.Status = history.ToStatus
+
+	return order, nil
+}
+
+func (o OrderService) markPaidAfterClose(ctx context.Context, order entity.Order, callback payment.Callback) (response entity.Order, err error) {
+	status := order.Status
+	paidAt, err := o.repository.MarkPaidAfterClose(ctx, entity.OrderStatusHistory{
+		OrderId:    order.ID,
+		FromStatus: &status,
+		ToStatus:   status,
+		Actor:      entity.ActorSystem,
+		Reason:     fmt.Sprintf("invoice %s paid after the order closed, refund required", callback.Id),
+	})
+	if err != nil {
+		return
+	}
+
+	if paidAt != nil {
+		order.PaidAfterCloseAt = paidAt
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelWarn, fmt.Sprintf("order %s paid after it was %s, refund required", order.ID, strings.ToLower(status)))
+	}
+
+	return order, nil
+}
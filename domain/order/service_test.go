@@ -0,0 +1,575 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/entity"
+	"github.com/ecommerce/infra/payment"
+	"github.com/stretchr/testify/require"
+)
+
+var svc = OrderService{}
+
+type mockOrderRepository struct{}
+type mockCartRepository struct{}
+type mockGateway struct{}
+
+// Checkout implements Repository.
+func (mockOrderRepository) Checkout(ctx context.Context, order entity.Order) (response []entity.Order, err error) {
+	return CheckoutOrder(order)
+}
+
+// GetById implements Repository.
+func (mockOrderRepository) GetById(ctx context.Context, id string) (order entity.Order, err error) {
+	return GetOrderById(id)
+}
+
+// IsMerchantOrder implements Repository.
+func (mockOrderRepository) IsMerchantOrder(ctx context.Context, id string, userId string) (ok bool, err error) {
+	return IsMerchantOrder(userId)
+}
+
+// UpdateStatus implements Repository.
+func (mockOrderRepository) UpdateStatus(ctx context.Context, history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+	return UpdateOrderStatus(history)
+}
+
+// GetHistory implements Repository.
+func (mockOrderRepository) GetHistory(ctx context.Context, id string) (histories []entity.OrderStatusHistory, err error) {
+	return GetOrderHistory()
+}
+
+// UpdateInvoice implements Repository.
+func (mockOrderRepository) UpdateInvoice(ctx context.Context, order entity.Order, expiresAt time.Time) (err error) {
+	return UpdateOrderInvoice(order)
+}
+
+// MarkPaidAfterClose implements Repository.
+func (mockOrderRepository) MarkPaidAfterClose(ctx context.Context, history entity.OrderStatusHistory) (paidAt *string, err error) {
+	return MarkPaidAfterClose(history)
+}
+
+// CreateInvoice implements payment.PaymentGateway.
+func (mockGateway) CreateInvoice(ctx context.Context, invoice payment.Invoice) (result payment.InvoiceResult, err error) {
+	return CreateInvoice(invoice)
+}
+
+// ExpireInvoice implements payment.PaymentGateway.
+func (mockGateway) ExpireInvoice(ctx context.Context, id string) (err error) {
+	return ExpireInvoice(id)
+}
+
+// VerifyCallback implements payment.PaymentGateway.
+func (mockGateway) VerifyCallback(signature string, body []byte) (callback payment.Callback, err error) {
+	return VerifyCallback(signature)
+}
+
+// GetItems implements CartRepository.
+func (mockCartRepository) GetItems(ctx context.Context, id string) (items []entity.CartItem, err error) {
+	return GetCartItems()
+}
+
+// DeleteItems implements CartRepository.
+func (mockCartRepository) DeleteItems(ctx context.Context, id string, skus []string) (err error) {
+	return DeleteCartItems(skus)
+}
+
+var (
+	CheckoutOrder      func(order entity.Order) (response []entity.Order, err error)
+	GetOrderById       func(id string) (order entity.Order, err error)
+	IsMerchantOrder    func(userId string) (ok bool, err error)
+	UpdateOrderStatus  func(history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error)
+	GetOrderHistory    func() (histories []entity.OrderStatusHistory, err error)
+	UpdateOrderInvoice func(order entity.Order) (err error)
+	MarkPaidAfterClose func(history entity.OrderStatusHistory) (paidAt *string, err error)
+	CreateInvoice      func(invoice payment.Invoice) (result payment.InvoiceResult, err error)
+	ExpireInvoice      func(id string) (err error)
+	VerifyCallback     func(signature string) (callback payment.Callback, err error)
+	GetCartItems       func() (items []entity.CartItem, err error)
+	DeleteCartItems    func(skus []string) (err error)
+)
+
+func init() {
+	svc = NewOrderService(mockOrderRepository{}, mockCartRepository{}, mockGateway{})
+}
+
+// priced stands in for the repository, every line costs 1000 and sku B is
+// sold by a second merchant.
+func priced(order entity.Order) ([]entity.Order, error) {
+	for i := range order.Details {
+		order.Details[i].MerchantId = 1
+		if order.Details[i].Sku == "B" {
+			order.Details[i].MerchantId = 2
+		}
+		order.Details[i].Price = 1000
+		order.Details[i].TotalPriceProduct = 1000 * order.Details[i].Quantity
+	}
+
+	return entity.NewOrder().SplitByMerchant(order), nil
+}
+
+func TestCheckout(t *testing.T) {
+	type testCase struct {
+		title          string
+		req            entity.Order
+		expectedErr    error
+		expectedOrders int
+		before         func()
+	}
+
+	var deleted []string
+	var cancelled int
+	var expired []string
+
+	var testCases = []testCase{
+		{
+			title:          "checkout success: explicit items keep the cart",
+			req:            entity.Order{UserId: "user-id", Details: []entity.OrderDetail{{Sku: "A", Quantity: 2}}},
+			expectedErr:    nil,
+			expectedOrders: 1,
+			before: func() {
+				deleted = nil
+				CheckoutOrder = priced
+				CreateInvoice = func(invoice payment.Invoice) (result payment.InvoiceResult, err error) {
+					return payment.InvoiceResult{Id: "inv-" + invoice.ExternalId, InvoiceUrl: "https://checkout.example.com/inv"}, nil
+				}
+				UpdateOrderInvoice = func(order entity.Order) (err error) {
+					return nil
+				}
+				DeleteCartItems = func(skus []string) (err error) {
+					deleted = skus
+					return nil
+				}
+			},
+		},
+		{
+			title:          "checkout success: cart items leave the cart",
+			req:            entity.Order{},
+			expectedErr:    nil,
+			expectedOrders: 1,
+			before: func() {
+				GetCartItems = func() (items []entity.CartItem, err error) {
+					return []entity.CartItem{{Sku: "A", Quantity: 2, Price: 1000}}, nil
+				}
+			},
+		},
+		{
+			title:          "checkout success: cart cleanup error is ignored",
+			req:            entity.Order{},
+			expectedErr:    nil,
+			expectedOrders: 1,
+			before: func() {
+				DeleteCartItems = func(skus []string) (err error) {
+					deleted = skus
+					return errors.New("redis unavailable")
+				}
+			},
+		},
+		{
+			title:          "checkout success: two merchants get an order each",
+			req:            entity.Order{UserId: "user-id", Details: []entity.OrderDetail{{Sku: "A", Quantity: 1}, {Sku: "B", Quantity: 1}}},
+			expectedErr:    nil,
+			expectedOrders: 2,
+			before: func() {
+				deleted = nil
+			},
+		},
+		{
+			title:          "checkout failed: invoice cannot be created",
+			req:            entity.Order{Details: []entity.OrderDetail{{Sku: "A", Quantity: 2}}},
+			expectedErr:    entity.ErrPaymentUnavailable,
+			expectedOrders: 1,
+			before: func() {
+				deleted = nil
+				cancelled = 0
+				CreateInvoice = func(invoice payment.Invoice) (result payment.InvoiceResult, err error) {
+					return payment.InvoiceResult{}, errors.New("gateway timeout")
+				}
+				UpdateOrderStatus = func(history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+					if history.ToStatus != entity.OrderStatusCancelled || history.Actor != entity.ActorSystem {
+						return history, errors.New("unexpected history")
+					}
+					cancelled++
+					return history, nil
+				}
+			},
+		},
+		{
+			title:          "checkout failed: one invoice failing cancels every order",
+			req:            entity.Order{Details: []entity.OrderDetail{{Sku: "A", Quantity: 1}, {Sku: "B", Quantity: 1}}},
+			expectedErr:    entity.ErrPaymentUnavailable,
+			expectedOrders: 2,
+			before: func() {
+				cancelled = 0
+				expired = nil
+				ExpireInvoice = func(id string) (err error) {
+					expired = append(expired, id)
+					return nil
+				}
+				invoices := 0
+				CreateInvoice = func(invoice payment.Invoice) (result payment.InvoiceResult, err error) {
+					invoices++
+					if invoices > 1 {
+						return payment.InvoiceResult{}, errors.New("gateway timeout")
+					}
+					return payment.InvoiceResult{Id: "inv-" + invoice.ExternalId, InvoiceUrl: "https://checkout.example.com/inv"}, nil
+				}
+			},
+		},
+		{
+			title:       "checkout failed: empty cart",
+			req:         entity.Order{},
+			expectedErr: entity.ErrOrderItemsIsRequired,
+			before: func() {
+				GetCartItems = func() (items []entity.CartItem, err error) {
+					return []entity.CartItem{}, nil
+				}
+			},
+		},
+		{
+			title:       "checkout failed: insufficient stock",
+			req:         entity.Order{Details: []entity.OrderDetail{{Sku: "A", Quantity: 2}}},
+			expectedErr: entity.ErrInsufficientStock,
+			before: func() {
+				deleted = nil
+				CheckoutOrder = func(order entity.Order) (response []entity.Order, err error) {
+					return nil, entity.ErrInsufficientStock
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, err := svc.Checkout(context.Background(), "user-id", "buyer@example.com", test.req)
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == entity.ErrPaymentUnavailable {
+				require.Equal(t, test.expectedOrders, cancelled)
+				require.Equal(t, test.expectedOrders-1, len(expired))
+				require.Nil(t, deleted)
+			}
+
+			if test.expectedErr == nil {
+				require.Len(t, response.Orders, test.expectedOrders)
+				require.Equal(t, 2000, response.TotalPrice)
+
+				merchants := map[int]bool{}
+				for _, order := range response.Orders {
+					require.Equal(t, "inv-"+order.ID, order.TrxId)
+					require.Equal(t, "https://checkout.example.com/inv", order.InvoiceUrl)
+					require.Len(t, order.Items, 1)
+					require.Equal(t, order.TotalPrice, order.Items[0].TotalPriceProduct)
+					merchants[order.MerchantId] = true
+				}
+				require.Len(t, merchants, test.expectedOrders)
+				require.Equal(t, len(test.req.Details) == 0, len(deleted) == 1)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	type testCase struct {
+		title          string
+		userId         string
+		role           string
+		status         string
+		expectedErr    error
+		expectedActor  string
+		expectedStatus string
+		before         func()
+	}
+
+	paidOrder := func(id string) (entity.Order, error) {
+		if id != "order-1" {
+			return entity.Order{}, sql.ErrNoRows
+		}
+		return entity.Order{ID: "order-1", UserId: "buyer-id", Status: entity.OrderStatusPaid}, nil
+	}
+
+	var recorded entity.OrderStatusHistory
+
+	var testCases = []testCase{
+		{
+			title:          "update status success: merchant starts processing",
+			userId:         "merchant-id",
+			role:           entity.RoleMerchant,
+			status:         entity.OrderStatusProcessing,
+			expectedErr:    nil,
+			expectedActor:  entity.ActorMerchant,
+			expectedStatus: entity.OrderStatusProcessing,
+			before: func() {
+				GetOrderById = paidOrder
+				IsMerchantOrder = func(userId string) (ok bool, err error) {
+					return userId == "merchant-id", nil
+				}
+				UpdateOrderStatus = func(history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+					recorded = history
+					return history, nil
+				}
+			},
+		},
+		{
+			title:          "update status success: admin refunds",
+			userId:         "admin-id",
+			role:           entity.RoleAdmin,
+			status:         entity.OrderStatusRefunded,
+			expectedErr:    nil,
+			expectedActor:  entity.ActorAdmin,
+			expectedStatus: entity.OrderStatusRefunded,
+			before:         func() {},
+		},
+		{
+			title:       "update status failed: buyer cannot start processing",
+			userId:      "buyer-id",
+			role:        entity.RoleUser,
+			status:      entity.OrderStatusProcessing,
+			expectedErr: entity.ErrOrderTransitionForbidden,
+			before:      func() {},
+		},
+		{
+			title:       "update status failed: paid order cannot be cancelled",
+			userId:      "buyer-id",
+			role:        entity.RoleUser,
+			status:      entity.OrderStatusCancelled,
+			expectedErr: entity.ErrOrderTransitionIsInvalid,
+			before:      func() {},
+		},
+		{
+			title:       "update status failed: stranger sees no order",
+			userId:      "stranger-id",
+			role:        entity.RoleMerchant,
+			status:      entity.OrderStatusProcessing,
+			expectedErr: entity.ErrOrderNotFound,
+			before:      func() {},
+		},
+		{
+			title:       "update status failed: concurrent move",
+			userId:      "merchant-id",
+			role:        entity.RoleMerchant,
+			status:      entity.OrderStatusProcessing,
+			expectedErr: entity.ErrOrderStatusConflict,
+			before: func() {
+				UpdateOrderStatus = func(history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+					return entity.OrderStatusHistory{}, entity.ErrOrderStatusConflict
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			req := entity.OrderStatusHistory{OrderId: "order-1", ToStatus: test.status, ActorId: &test.userId}
+			response, err := svc.UpdateStatus(context.Background(), test.userId, test.role, req)
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, test.expectedStatus, response.Status)
+				require.Equal(t, test.expectedActor, recorded.Actor)
+				require.Equal(t, entity.OrderStatusPaid, *recorded.FromStatus)
+			}
+		})
+	}
+
+	t.Run("err : order not found", func(t *testing.T) {
+		_, err := svc.UpdateStatus(context.Background(), "buyer-id", entity.RoleUser, entity.OrderStatusHistory{OrderId: "missing"})
+		require.Equal(t, entity.ErrOrderNotFound, err)
+	})
+}
+
+func TestGetStatusHistory(t *testing.T) {
+	t.Run("success : buyer reads history", func(t *testing.T) {
+		GetOrderById = func(id string) (order entity.Order, err error) {
+			return entity.Order{ID: id, UserId: "buyer-id"}, nil
+		}
+		GetOrderHistory = func() (histories []entity.OrderStatusHistory, err error) {
+			return []entity.OrderStatusHistory{{ToStatus: entity.OrderStatusPendingPayment, Actor: entity.ActorBuyer}}, nil
+		}
+
+		response, err := svc.GetStatusHistory(context.Background(), "buyer-id", entity.RoleUser, "order-1")
+		require.NoError(t, err)
+		require.Len(t, response, 1)
+		require.Nil(t, response[0].FromStatus)
+	})
+
+	t.Run("err : stranger sees no order", func(t *testing.T) {
+		IsMerchantOrder = func(userId string) (ok bool, err error) {
+			return false, nil
+		}
+
+		_, err := svc.GetStatusHistory(context.Background(), "stranger-id", entity.RoleUser, "order-1")
+		require.Equal(t, entity.ErrOrderNotFound, err)
+	})
+}
+
+func TestPaymentCallback(t *testing.T) {
+	type testCase struct {
+		title          string
+		expectedErr    error
+		expectedStatus string
+		before         func()
+	}
+
+	pending := func(id string) (order entity.Order, err error) {
+		if id != "order-1" {
+			return entity.Order{}, sql.ErrNoRows
+		}
+		return entity.Order{ID: id, TotalPrice: 25000, Status: entity.OrderStatusPendingPayment}, nil
+	}
+
+	paid := func(signature string) (callback payment.Callback, err error) {
+		return payment.Callback{Id: "inv-1", ExternalId: "order-1", Status: payment.StatusPaid, Amount: 25000}, nil
+	}
+
+	updates := 0
+	recorded := 0
+
+	var testCases = []testCase{
+		{
+			title:          "payment callback success: order moves to paid",
+			expectedErr:    nil,
+			expectedStatus: entity.OrderStatusPaid,
+			before: func() {
+				VerifyCallback = paid
+				GetOrderById = pending
+				UpdateOrderStatus = func(history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+					updates++
+					if history.ToStatus != entity.OrderStatusPaid || history.Actor != entity.ActorSystem {
+						return history, errors.New("unexpected history")
+					}
+					return history, nil
+				}
+			},
+		},
+		{
+			title:          "payment callback success: repeated callback is a no-op",
+			expectedErr:    nil,
+			expectedStatus: entity.OrderStatusProcessing,
+			before: func() {
+				updates = 0
+				GetOrderById = func(id string) (order entity.Order, err error) {
+					return entity.Order{ID: id, TotalPrice: 25000, Status: entity.OrderStatusProcessing}, nil
+				}
+			},
+		},
+		{
+			title:          "payment callback success: concurrent callback already paid",
+			expectedErr:    nil,
+			expectedStatus: entity.OrderStatusPaid,
+			before: func() {
+				calls := 0
+				GetOrderById = func(id string) (order entity.Order, err error) {
+					calls++
+					if calls == 1 {
+						return pending(id)
+					}
+					return entity.Order{ID: id, TotalPrice: 25000, Status: entity.OrderStatusPaid}, nil
+				}
+				UpdateOrderStatus = func(history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+					return entity.OrderStatusHistory{}, entity.ErrOrderStatusConflict
+				}
+			},
+		},
+		{
+			title:       "payment callback failed: signature is invalid",
+			expectedErr: payment.ErrSignatureIsInvalid,
+			before: func() {
+				VerifyCallback = func(signature string) (callback payment.Callback, err error) {
+					return payment.Callback{}, payment.ErrSignatureIsInvalid
+				}
+			},
+		},
+		{
+			title:       "payment callback failed: amount does not match",
+			expectedErr: entity.ErrPaymentMismatch,
+			before: func() {
+				VerifyCallback = func(signature string) (callback payment.Callback, err error) {
+					return payment.Callback{ExternalId: "order-1", Status: payment.StatusPaid, Amount: 1}, nil
+				}
+				GetOrderById = pending
+			},
+		},
+		{
+			title:          "payment callback success: paid after expiry is recorded",
+			expectedErr:    nil,
+			expectedStatus: entity.OrderStatusExpired,
+			before: func() {
+				VerifyCallback = paid
+				GetOrderById = func(id string) (order entity.Order, err error) {
+					return entity.Order{ID: id, TotalPrice: 25000, Status: entity.OrderStatusExpired}, nil
+				}
+				MarkPaidAfterClose = func(history entity.OrderStatusHistory) (paidAt *string, err error) {
+					if history.ToStatus != entity.OrderStatusExpired || *history.FromStatus != entity.OrderStatusExpired || history.Actor != entity.ActorSystem {
+						return nil, errors.New("unexpected history")
+					}
+					recorded++
+					if recorded > 1 {
+						return nil, nil
+					}
+					at := "2023-12-20T12:00:00Z"
+					return &at, nil
+				}
+			},
+		},
+		{
+			title:          "payment callback success: repeated payment after expiry is recorded once",
+			expectedErr:    nil,
+			expectedStatus: entity.OrderStatusExpired,
+			before:         func() {},
+		},
+		{
+			title:          "payment callback success: order expired while paying",
+			expectedErr:    nil,
+			expectedStatus: entity.OrderStatusExpired,
+			before: func() {
+				calls := 0
+				GetOrderById = func(id string) (order entity.Order, err error) {
+					calls++
+					if calls == 1 {
+						return pending(id)
+					}
+					return entity.Order{ID: id, TotalPrice: 25000, Status: entity.OrderStatusExpired}, nil
+				}
+				UpdateOrderStatus = func(history entity.OrderStatusHistory) (response entity.OrderStatusHistory, err error) {
+					return entity.OrderStatusHistory{}, entity.ErrOrderStatusConflict
+				}
+			},
+		},
+		{
+			title:       "payment callback failed: order not found",
+			expectedErr: entity.ErrOrderNotFound,
+			before: func() {
+				VerifyCallback = func(signature string) (callback payment.Callback, err error) {
+					return payment.Callback{ExternalId: "missing", Status: payment.StatusPaid}, nil
+				}
+				GetOrderById = pending
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			response, err := svc.PaymentCallback(context.Background(), "signature", []byte("{}"))
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, test.expectedStatus, response.Status)
+			}
+		})
+	}
+
+	require.Equal(t, 0, updates)
+	// the first report records the payment, the later ones find it recorded
+	require.Equal(t, 3, recorded)
+}
@@ -1,10 +1,15 @@
 package product
 
 import (
+	"context"
+
+	"github.com/ecommerce/config"
 	categoryRepository "github.com/ecommerce/domain/category/repository"
 	merchantRepository "github.com/ecommerce/domain/merchant/repository"
 	productRepository "github.com/ecommerce/domain/product/repository"
+	"github.com/ecommerce/entity"
 	"github.com/ecommerce/infra/middleware"
+	"github.com/ecommerce/infra/notifier"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
 )
@@ -22,10 +27,32 @@ func RegisterServiceProduct(router fiber.Router, db DB) {
 
 	var productRouter = router.Group("/v1/products")
 	{
-		productRouter.Post("/", middleware.AuthMiddleware(), handler.CreateProducts)
-		productRouter.Get("/", middleware.AuthMiddleware(), handler.GetListProduct)
-		productRouter.Get("/id/:product_id", middleware.AuthMiddleware(), handler.GetDetailProduct)
-		productRouter.Put("/id/:product_id", middleware.AuthMiddleware(), handler.UpdateProduct)
+		productRouter.Post("/", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.CreateProducts)
+		productRouter.Get("/", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.GetListProduct)
+		productRouter.Get("/id/:product_id", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.GetDetailProduct)
+		productRouter.Put("/id/:product_id", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.UpdateProduct)
+		productRouter.Patch("/id/:product_id", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.PatchProduct)
+		productRouter.Delete("/id/:product_id", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.DeleteProduct)
+		productRouter.Post("/id/:product_id/restore", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.RestoreProduct)
+		productRouter.Post("/id/:product_id/images", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.AttachProductImage)
+		productRouter.Put("/id/:product_id/images/order", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.ReorderProductImages)
+		productRouter.Delete("/id/:product_id/images/:image_id", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.RemoveProductImage)
+		productRouter.Post("/id/:product_id/stock/adjustments", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.AdjustStock)
+		productRouter.Get("/id/:product_id/stock/movements", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.GetStockMovements)
+		productRouter.Post("/import", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.ImportProducts)
+		productRouter.Get("/export", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.ExportProducts)
 		productRouter.Get("/detail/:sku", middleware.AuthMiddleware(), handler.GetDetailProductUserPerspective)
+		productRouter.Get("/catalog", handler.GetCatalog)
+		productRouter.Get("/search", handler.SearchProduct)
 	}
 }
+
+func RunPurgeJob(ctx context.Context, db DB, cfg config.Product) {
+	productRepository := productRepository.NewProductRepository(db.Dbx)
+	NewPurgeJob(productRepository, cfg).Run(ctx)
+}
+
+func RunLowStockJob(ctx context.Context, db DB, notifier notifier.Notifier, cfg config.Product) {
+	productRepository := productRepository.NewProductRepository(db.Dbx)
+	NewLowStockJob(productRepository, notifier, cfg).Run(ctx)
+}
@@ -1,8 +1,11 @@
 package product
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/ecommerce/dto"
 	"github.com/ecommerce/entity"
@@ -10,6 +13,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+const exportTimeout = 5 * time.Minute
+
 type ProductHandler struct {
 	service Service
 }
@@ -92,6 +97,12 @@ func (p ProductHandler) GetDetailProduct(c *fiber.Ctx) error {
 		return WriteError(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, entity.NewProduct().ETag(response.Version))
+
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" && entity.NewProduct().MatchETag(ifNoneMatch, response.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return WriteSuccess(c, "get product success", response, nil, fiber.StatusOK)
 }
 
@@ -117,6 +128,12 @@ func (p ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	}
 	model.ID = productIdValue
 
+	model.Version, err = entity.NewProduct().ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
 	if err := p.service.UpdateProduct(c.UserContext(), model, id); err != nil {
 		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
 		return WriteError(c, err)
@@ -125,6 +142,41 @@ func (p ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	return WriteSuccess(c, "update product success", nil, nil, fiber.StatusOK)
 }
 
+func (p ProductHandler) PatchProduct(c *fiber.Ctx) error {
+	var req dto.UpdateProductRequest
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	patch, err := entity.NewProduct().ValidatePatch(req)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	patch.Version, err = entity.NewProduct().ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := p.service.PatchProduct(c.UserContext(), productIdValue, patch, id); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "update product success", nil, nil, fiber.StatusOK)
+}
+
 func (p ProductHandler) GetDetailProductUserPerspective(c *fiber.Ctx) error {
 	productSku := c.Params("sku")
 
@@ -136,3 +188,282 @@ func (p ProductHandler) GetDetailProductUserPerspective(c *fiber.Ctx) error {
 
 	return WriteSuccess(c, "get products success", response, nil, fiber.StatusOK)
 }
+
+func (p ProductHandler) GetCatalog(c *fiber.Ctx) error {
+	var req dto.GetCatalogRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, entity.ErrInvalidQueryParam)
+	}
+
+	filter, err := entity.NewProduct().ValidateCatalogFilter(req)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	responses, totalData, err := p.service.GetCatalog(c.UserContext(), filter)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	paginationResponse := dto.NewPaginationResponse(filter.Query, filter.Limit, filter.Page, totalData)
+
+	return WriteSuccess(c, "get catalog success", responses, paginationResponse, fiber.StatusOK)
+}
+
+func (p ProductHandler) SearchProduct(c *fiber.Ctx) error {
+	var req dto.GetCatalogRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, entity.ErrInvalidQueryParam)
+	}
+
+	filter, err := entity.NewProduct().ValidateSearchFilter(req)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	responses, totalData, err := p.service.SearchProduct(c.UserContext(), filter)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	paginationResponse := dto.NewPaginationResponse(filter.Query, filter.Limit, filter.Page, totalData)
+
+	return WriteSuccess(c, "search products success", responses, paginationResponse, fiber.StatusOK)
+}
+
+func (p ProductHandler) DeleteProduct(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := p.service.DeleteProduct(c.UserContext(), productIdValue, id); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "delete product success", nil, nil, fiber.StatusOK)
+}
+
+func (p ProductHandler) RestoreProduct(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := p.service.RestoreProduct(c.UserContext(), productIdValue, id); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "restore product success", nil, nil, fiber.StatusOK)
+}
+
+func (p ProductHandler) AttachProductImage(c *fiber.Ctx) error {
+	var req dto.AttachProductImageRequest
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	image, err := entity.NewProductImage().Validate(req, productIdValue)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, err := p.service.AttachProductImage(c.UserContext(), image, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "attach product image success", response, nil, fiber.StatusCreated)
+}
+
+func (p ProductHandler) ReorderProductImages(c *fiber.Ctx) error {
+	var req dto.ReorderProductImagesRequest
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	response, err := p.service.ReorderProductImages(c.UserContext(), productIdValue, req.ImageIds, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "reorder product images success", response, nil, fiber.StatusOK)
+}
+
+func (p ProductHandler) RemoveProductImage(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+	imageId := c.Params("image_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	imageIdValue, err := strconv.Atoi(imageId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := p.service.RemoveProductImage(c.UserContext(), productIdValue, imageIdValue, id); err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "remove product image success", nil, nil, fiber.StatusOK)
+}
+
+func (p ProductHandler) ImportProducts(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, entity.ErrImportFileIsRequired)
+	}
+
+	if file.Size > entity.MaxImportFileSize {
+		err = entity.ErrImportFileIsTooLarge
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	source, err := file.Open()
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+	defer source.Close()
+
+	response, err := p.service.ImportProducts(c.UserContext(), source, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "import product success", response, nil, fiber.StatusOK)
+}
+
+func (p ProductHandler) ExportProducts(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+
+	export, err := p.service.ExportProducts(c.UserContext(), id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="products.csv"`)
+
+	// the body is written after the handler returns, so the request context is
+	// no longer usable and a failure can only be logged
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		if err := export(ctx, w); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		}
+	})
+
+	return nil
+}
+
+func (p ProductHandler) AdjustStock(c *fiber.Ctx) error {
+	var req dto.CreateStockAdjustmentRequest
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return err
+	}
+
+	movement, err := entity.NewInventoryMovement().Validate(req, productIdValue, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	response, err := p.service.AdjustStock(c.UserContext(), movement, id)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	return WriteSuccess(c, "adjust stock success", response, nil, fiber.StatusCreated)
+}
+
+func (p ProductHandler) GetStockMovements(c *fiber.Ctx) error {
+	id := c.Locals("id").(string)
+	productId := c.Params("product_id")
+
+	productIdValue, err := strconv.Atoi(productId)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	limitValue, pageValue, err := entity.NewInventoryMovement().ValidatePage(c.QueryInt("limit"), c.QueryInt("page"))
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	responses, totalData, err := p.service.GetStockMovements(c.UserContext(), productIdValue, id, limitValue, pageValue)
+	if err != nil {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		return WriteError(c, err)
+	}
+
+	paginationResponse := dto.NewPaginationResponse("", limitValue, pageValue, totalData)
+
+	return WriteSuccess(c, "get stock movements success", responses, paginationResponse, fiber.StatusOK)
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/ecommerce/config"
 	"github.com/ecommerce/dto"
@@ -41,6 +44,66 @@ func (mockProductService) GetListProduct(ctx context.Context, token string, quer
 	return GetListProductHandler()
 }
 
+// GetCatalog implements Service.
+func (mockProductService) GetCatalog(ctx context.Context, filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+	return GetCatalogHandler(filter)
+}
+
+// SearchProduct implements Service.
+func (mockProductService) SearchProduct(ctx context.Context, filter entity.CatalogFilter) (response []dto.SearchProductResponse, totalData int, err error) {
+	return SearchProductHandler(filter)
+}
+
+// DeleteProduct implements Service.
+func (mockProductService) DeleteProduct(ctx context.Context, id int, token string) (err error) {
+	return DeleteProductHandler()
+}
+
+// RestoreProduct implements Service.
+func (mockProductService) RestoreProduct(ctx context.Context, id int, token string) (err error) {
+	return RestoreProductHandler()
+}
+
+// PatchProduct implements Service.
+func (mockProductService) PatchProduct(ctx context.Context, id int, patch entity.ProductPatch, token string) (err error) {
+	return PatchProductHandler(patch)
+}
+
+// AttachProductImage implements Service.
+func (mockProductService) AttachProductImage(ctx context.Context, image entity.ProductImage, token string) (response dto.ProductImageResponse, err error) {
+	return AttachProductImageHandler(image)
+}
+
+// ReorderProductImages implements Service.
+func (mockProductService) ReorderProductImages(ctx context.Context, productId int, ids []int, token string) (response []dto.ProductImageResponse, err error) {
+	return ReorderProductImagesHandler(ids)
+}
+
+// RemoveProductImage implements Service.
+func (mockProductService) RemoveProductImage(ctx context.Context, productId int, imageId int, token string) (err error) {
+	return RemoveProductImageHandler()
+}
+
+// ImportProducts implements Service.
+func (mockProductService) ImportProducts(ctx context.Context, file io.Reader, token string) (response dto.ImportProductResponse, err error) {
+	return ImportProductsHandler(file)
+}
+
+// ExportProducts implements Service.
+func (mockProductService) ExportProducts(ctx context.Context, token string) (export func(ctx context.Context, w io.Writer) error, err error) {
+	return ExportProductsHandler()
+}
+
+// AdjustStock implements Service.
+func (mockProductService) AdjustStock(ctx context.Context, movement entity.InventoryMovement, token string) (response dto.InventoryMovementResponse, err error) {
+	return AdjustStockHandler(movement)
+}
+
+// GetStockMovements implements Service.
+func (mockProductService) GetStockMovements(ctx context.Context, productId int, token string, limit int, page int) (response []dto.InventoryMovementResponse, totalData int, err error) {
+	return GetStockMovementsHandler(limit, page)
+}
+
 // UpdateProduct implements Service.
 func (mockProductService) UpdateProduct(ctx context.Context, req entity.Product, token string) (err error) {
 	return UpdateProductHandler()
@@ -52,6 +115,18 @@ var (
 	GetDetailProductUserPerspectiveHandler func() (response dto.GetDetailProductUserPerspectiveResponse, err error)
 	GetListProductHandler                  func() (response []dto.GetListProductResponse, totalData int, err error)
 	UpdateProductHandler                   func() (err error)
+	GetCatalogHandler                      func(filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error)
+	SearchProductHandler                   func(filter entity.CatalogFilter) (response []dto.SearchProductResponse, totalData int, err error)
+	DeleteProductHandler                   func() (err error)
+	PatchProductHandler                    func(patch entity.ProductPatch) (err error)
+	RestoreProductHandler                  func() (err error)
+	AttachProductImageHandler              func(image entity.ProductImage) (response dto.ProductImageResponse, err error)
+	ReorderProductImagesHandler            func(ids []int) (response []dto.ProductImageResponse, err error)
+	RemoveProductImageHandler              func() (err error)
+	AdjustStockHandler                     func(movement entity.InventoryMovement) (response dto.InventoryMovementResponse, err error)
+	GetStockMovementsHandler               func(limit, page int) (response []dto.InventoryMovementResponse, totalData int, err error)
+	ImportProductsHandler                  func(file io.Reader) (response dto.ImportProductResponse, err error)
+	ExportProductsHandler                  func() (export func(ctx context.Context, w io.Writer) error, err error)
 	jwtSecret                              config.JWT
 )
 
@@ -447,6 +522,9 @@ func TestCreateProductHandler(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -551,6 +629,21 @@ func TestGetDetailProductHandler(t *testing.T) {
 				return errors.New("endpoint not found")
 			},
 		},
+		{
+			title:              "get detail product failed caller has no merchant",
+			expectedErr:        entity.ErrMerchantNotFound,
+			expectedValue:      dto.GetDetailProductResponse{},
+			expectedStatusCode: fiber.StatusForbidden,
+			endpoint:           "/v1/products/id/1",
+			requestHeader:      "Bearer ",
+			before: func() error {
+				GetDetailProductHandler = func() (response dto.GetDetailProductResponse, err error) {
+					return dto.GetDetailProductResponse{}, entity.ErrMerchantNotFound
+				}
+
+				return entity.ErrMerchantNotFound
+			},
+		},
 		{
 			title:              "get detail product failed product not found",
 			expectedErr:        entity.ErrProductNotFound,
@@ -593,6 +686,9 @@ func TestGetDetailProductHandler(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -746,6 +842,9 @@ func TestGetDetailProductUserPerspective(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -892,6 +991,9 @@ func TestGetListProductHandler(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -1260,6 +1362,9 @@ func TestUpdateProductHandler(t *testing.T) {
 				ID:    "1",
 				Email: "user@gmail.com",
 				Role:  "user",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
 			})
 			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 			require.NoError(t, err)
@@ -1282,3 +1387,911 @@ func TestUpdateProductHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCatalogHandler(t *testing.T) {
+	type testCase struct {
+		title              string
+		expectedFilter     entity.CatalogFilter
+		expectedStatusCode int
+		endpoint           string
+		before             func()
+	}
+
+	var received entity.CatalogFilter
+
+	var testCases = []testCase{
+		{
+			title: "get catalog success",
+			expectedFilter: entity.CatalogFilter{
+				CategoryId: 2,
+				MinPrice:   1000,
+				MaxPrice:   5000,
+				City:       "Jakarta",
+				InStock:    true,
+				Sort:       entity.SortPriceAsc,
+				Limit:      5,
+				Page:       2,
+			},
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/catalog?category_id=2&min_price=1000&max_price=5000&city=Jakarta&in_stock=true&sort=price_asc&limit=5&page=2",
+			before: func() {
+				GetCatalogHandler = func(filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+					received = filter
+					return []dto.GetCatalogProductResponse{
+						{
+							ID:    1,
+							Sku:   "test",
+							Name:  "test",
+							Price: 1000,
+							Stock: 10,
+						},
+					}, 6, nil
+				}
+			},
+		},
+		{
+			title: "get catalog success with defaults",
+			expectedFilter: entity.CatalogFilter{
+				Sort:  entity.SortNewest,
+				Limit: entity.DefaultLimit,
+				Page:  1,
+			},
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/catalog",
+			before: func() {
+				GetCatalogHandler = func(filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+					received = filter
+					return []dto.GetCatalogProductResponse{}, 0, nil
+				}
+			},
+		},
+		{
+			title: "get catalog success with hostile query passed through as data",
+			expectedFilter: entity.CatalogFilter{
+				Query: "'; DROP TABLE products; --",
+				Sort:  entity.SortNewest,
+				Limit: entity.DefaultLimit,
+				Page:  1,
+			},
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/catalog?query=%27%3B%20DROP%20TABLE%20products%3B%20--",
+			before: func() {
+				GetCatalogHandler = func(filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+					received = filter
+					return []dto.GetCatalogProductResponse{}, 0, nil
+				}
+			},
+		},
+		{
+			title:              "get catalog failed invalid sort",
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/catalog?sort=random",
+			before: func() {
+				GetCatalogHandler = func(filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+					received = filter
+					return nil, 0, nil
+				}
+			},
+		},
+		{
+			title:              "get catalog failed invalid query param",
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/catalog?min_price=abc",
+			before: func() {
+				GetCatalogHandler = func(filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+					received = filter
+					return nil, 0, nil
+				}
+			},
+		},
+		{
+			title:              "get catalog failed internal server error",
+			expectedFilter:     entity.CatalogFilter{Sort: entity.SortNewest, Limit: entity.DefaultLimit, Page: 1},
+			expectedStatusCode: fiber.StatusInternalServerError,
+			endpoint:           "/v1/products/catalog",
+			before: func() {
+				GetCatalogHandler = func(filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+					received = filter
+					return nil, 0, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+			received = entity.CatalogFilter{}
+
+			test.before()
+
+			mockService := mockProductService{}
+			handler := NewProductHandler(mockService)
+
+			router.Get("/v1/products/catalog", handler.GetCatalog)
+
+			request := httptest.NewRequest(fiber.MethodGet, test.endpoint, nil)
+
+			resp, _ := router.Test(request, 1)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			require.Equal(t, test.expectedFilter, received)
+		})
+	}
+}
+
+func TestSearchProductHandler(t *testing.T) {
+	type testCase struct {
+		title              string
+		expectedFilter     entity.CatalogFilter
+		expectedStatusCode int
+		endpoint           string
+		before             func()
+	}
+
+	var received entity.CatalogFilter
+
+	var testCases = []testCase{
+		{
+			title: "search product success",
+			expectedFilter: entity.CatalogFilter{
+				Query:      "kopi arabika",
+				CategoryId: 1,
+				Sort:       entity.SortRelevance,
+				Limit:      entity.DefaultLimit,
+				Page:       1,
+			},
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/search?query=kopi%20arabika&category_id=1&sort=price_asc",
+			before: func() {
+				SearchProductHandler = func(filter entity.CatalogFilter) (response []dto.SearchProductResponse, totalData int, err error) {
+					received = filter
+					return []dto.SearchProductResponse{
+						{
+							ID:      1,
+							Name:    "Kopi Arabika",
+							Snippet: "<mark>kopi</mark> <mark>arabika</mark>",
+							Rank:    0.9,
+						},
+					}, 1, nil
+				}
+			},
+		},
+		{
+			title:              "search product failed query is required",
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/search",
+			before: func() {
+				SearchProductHandler = func(filter entity.CatalogFilter) (response []dto.SearchProductResponse, totalData int, err error) {
+					received = filter
+					return nil, 0, nil
+				}
+			},
+		},
+		{
+			title:              "search product failed internal server error",
+			expectedFilter:     entity.CatalogFilter{Query: "kopi", Sort: entity.SortRelevance, Limit: entity.DefaultLimit, Page: 1},
+			expectedStatusCode: fiber.StatusInternalServerError,
+			endpoint:           "/v1/products/search?query=kopi",
+			before: func() {
+				SearchProductHandler = func(filter entity.CatalogFilter) (response []dto.SearchProductResponse, totalData int, err error) {
+					received = filter
+					return nil, 0, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+			received = entity.CatalogFilter{}
+
+			test.before()
+
+			mockService := mockProductService{}
+			handler := NewProductHandler(mockService)
+
+			router.Get("/v1/products/search", handler.SearchProduct)
+
+			request := httptest.NewRequest(fiber.MethodGet, test.endpoint, nil)
+
+			resp, _ := router.Test(request, 1)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			require.Equal(t, test.expectedFilter, received)
+		})
+	}
+}
+
+func TestDeleteAndRestoreProductHandler(t *testing.T) {
+	type testCase struct {
+		title              string
+		method             string
+		expectedStatusCode int
+		endpoint           string
+		role               string
+		before             func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:              "delete product success",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/id/1",
+			role:               entity.RoleMerchant,
+			before: func() {
+				DeleteProductHandler = func() (err error) {
+					return nil
+				}
+			},
+		},
+		{
+			title:              "delete product failed product not found",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: fiber.StatusNotFound,
+			endpoint:           "/v1/products/id/1",
+			role:               entity.RoleMerchant,
+			before: func() {
+				DeleteProductHandler = func() (err error) {
+					return entity.ErrProductNotFound
+				}
+			},
+		},
+		{
+			title:              "delete product failed forbidden role",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: fiber.StatusForbidden,
+			endpoint:           "/v1/products/id/1",
+			role:               entity.RoleUser,
+			before: func() {
+				DeleteProductHandler = func() (err error) {
+					return nil
+				}
+			},
+		},
+		{
+			title:              "restore product success",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/id/1/restore",
+			role:               entity.RoleMerchant,
+			before: func() {
+				RestoreProductHandler = func() (err error) {
+					return nil
+				}
+			},
+		},
+		{
+			title:              "restore product failed product not found",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusNotFound,
+			endpoint:           "/v1/products/id/1/restore",
+			role:               entity.RoleMerchant,
+			before: func() {
+				RestoreProductHandler = func() (err error) {
+					return entity.ErrProductNotFound
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+
+			test.before()
+
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+				ID:    "1",
+				Email: "user@gmail.com",
+				Role:  test.role,
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+			})
+			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+			require.NoError(t, err)
+
+			mockService := mockProductService{}
+			handler := NewProductHandler(mockService)
+
+			router.Delete("/v1/products/id/:product_id", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.DeleteProduct)
+			router.Post("/v1/products/id/:product_id/restore", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.RestoreProduct)
+
+			request := httptest.NewRequest(test.method, test.endpoint, nil)
+			request.Header.Set("Authorization", "Bearer "+signedToken)
+
+			resp, _ := router.Test(request, 1)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestPatchProductHandler(t *testing.T) {
+	type testCase struct {
+		title              string
+		expectedStatusCode int
+		body               string
+		before             func()
+	}
+
+	var received entity.ProductPatch
+
+	var testCases = []testCase{
+		{
+			title:              "patch product success",
+			expectedStatusCode: fiber.StatusOK,
+			body:               `{"price": 15000}`,
+			before: func() {
+				PatchProductHandler = func(patch entity.ProductPatch) (err error) {
+					received = patch
+					return nil
+				}
+			},
+		},
+		{
+			title:              "patch product failed no fields",
+			expectedStatusCode: fiber.StatusBadRequest,
+			body:               `{}`,
+			before: func() {
+				PatchProductHandler = func(patch entity.ProductPatch) (err error) {
+					received = patch
+					return nil
+				}
+			},
+		},
+		{
+			title:              "patch product failed invalid stock",
+			expectedStatusCode: fiber.StatusBadRequest,
+			body:               `{"stock": -1}`,
+			before: func() {
+				PatchProductHandler = func(patch entity.ProductPatch) (err error) {
+					received = patch
+					return nil
+				}
+			},
+		},
+		{
+			title:              "patch product failed product not found",
+			expectedStatusCode: fiber.StatusNotFound,
+			body:               `{"name": "new name"}`,
+			before: func() {
+				PatchProductHandler = func(patch entity.ProductPatch) (err error) {
+					received = patch
+					return entity.ErrProductNotFound
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+			received = entity.ProductPatch{}
+
+			test.before()
+
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+				ID:    "1",
+				Email: "user@gmail.com",
+				Role:  entity.RoleMerchant,
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+			})
+			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+			require.NoError(t, err)
+
+			mockService := mockProductService{}
+			handler := NewProductHandler(mockService)
+
+			router.Patch("/v1/products/id/:product_id", middleware.AuthMiddleware(), handler.PatchProduct)
+
+			request := httptest.NewRequest(fiber.MethodPatch, "/v1/products/id/1", bytes.NewBufferString(test.body))
+			request.Header.Set("Authorization", "Bearer "+signedToken)
+			request.Header.Set("Content-Type", "application/json")
+
+			resp, _ := router.Test(request, 1)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedStatusCode == fiber.StatusOK {
+				require.NotNil(t, received.Price)
+				require.Equal(t, 15000, *received.Price)
+				require.Nil(t, received.Name)
+			}
+		})
+	}
+}
+
+func TestProductConditionalRequestHandler(t *testing.T) {
+	type testCase struct {
+		title              string
+		method             string
+		header             string
+		value              string
+		expectedStatusCode int
+		expectedVersion    int
+	}
+
+	var receivedVersion int
+
+	GetDetailProductHandler = func() (response dto.GetDetailProductResponse, err error) {
+		return dto.GetDetailProductResponse{ID: 1, Name: "test", Version: 3}, nil
+	}
+	UpdateProductHandler = func() (err error) {
+		return nil
+	}
+	PatchProductHandler = func(patch entity.ProductPatch) (err error) {
+		receivedVersion = patch.Version
+		if patch.Version != 0 && patch.Version != 3 {
+			return entity.ErrVersionMismatch
+		}
+		return nil
+	}
+
+	var testCases = []testCase{
+		{
+			title:              "get product returns etag",
+			method:             fiber.MethodGet,
+			expectedStatusCode: fiber.StatusOK,
+		},
+		{
+			title:              "get product not modified",
+			method:             fiber.MethodGet,
+			header:             fiber.HeaderIfNoneMatch,
+			value:              `"3"`,
+			expectedStatusCode: fiber.StatusNotModified,
+		},
+		{
+			title:              "get product modified since",
+			method:             fiber.MethodGet,
+			header:             fiber.HeaderIfNoneMatch,
+			value:              `"2"`,
+			expectedStatusCode: fiber.StatusOK,
+		},
+		{
+			title:              "patch product matching version",
+			method:             fiber.MethodPatch,
+			header:             fiber.HeaderIfMatch,
+			value:              `"3"`,
+			expectedStatusCode: fiber.StatusOK,
+			expectedVersion:    3,
+		},
+		{
+			title:              "patch product stale version",
+			method:             fiber.MethodPatch,
+			header:             fiber.HeaderIfMatch,
+			value:              `"2"`,
+			expectedStatusCode: fiber.StatusPreconditionFailed,
+			expectedVersion:    2,
+		},
+		{
+			title:              "patch product malformed if match",
+			method:             fiber.MethodPatch,
+			header:             fiber.HeaderIfMatch,
+			value:              `version-3`,
+			expectedStatusCode: fiber.StatusPreconditionFailed,
+		},
+		{
+			title:              "put product malformed if match",
+			method:             fiber.MethodPut,
+			header:             fiber.HeaderIfMatch,
+			value:              `version-3`,
+			expectedStatusCode: fiber.StatusPreconditionFailed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+			receivedVersion = 0
+
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+				ID:    "1",
+				Email: "user@gmail.com",
+				Role:  entity.RoleMerchant,
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+			})
+			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+			require.NoError(t, err)
+
+			mockService := mockProductService{}
+			handler := NewProductHandler(mockService)
+
+			router.Get("/v1/products/id/:product_id", middleware.AuthMiddleware(), handler.GetDetailProduct)
+			router.Put("/v1/products/id/:product_id", middleware.AuthMiddleware(), handler.UpdateProduct)
+			router.Patch("/v1/products/id/:product_id", middleware.AuthMiddleware(), handler.PatchProduct)
+
+			var body *bytes.Buffer
+			switch test.method {
+			case fiber.MethodPut:
+				body = bytes.NewBufferString(`{"name":"test","description":"test","price":1000,"stock":10,"category_id":1,"image_url":"test.png"}`)
+			case fiber.MethodPatch:
+				body = bytes.NewBufferString(`{"price":1000}`)
+			default:
+				body = bytes.NewBuffer(nil)
+			}
+
+			request := httptest.NewRequest(test.method, "/v1/products/id/1", body)
+			request.Header.Set("Authorization", "Bearer "+signedToken)
+			request.Header.Set("Content-Type", "application/json")
+			if test.header != "" {
+				request.Header.Set(test.header, test.value)
+			}
+
+			resp, _ := router.Test(request, 1)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			require.Equal(t, test.expectedVersion, receivedVersion)
+
+			if test.method == fiber.MethodGet {
+				require.Equal(t, `"3"`, resp.Header.Get(fiber.HeaderETag))
+			}
+		})
+	}
+}
+
+func TestProductImageHandler(t *testing.T) {
+	type testCase struct {
+		title              string
+		method             string
+		expectedStatusCode int
+		endpoint           string
+		body               string
+		before             func()
+	}
+
+	var attached entity.ProductImage
+	var reordered []int
+
+	var testCases = []testCase{
+		{
+			title:              "attach product image success",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusCreated,
+			endpoint:           "/v1/products/id/7/images",
+			body:               `{"image_url":"https://res.cloudinary.com/demo/image.png","alt_text":"front"}`,
+			before: func() {
+				AttachProductImageHandler = func(image entity.ProductImage) (response dto.ProductImageResponse, err error) {
+					attached = image
+					return dto.ProductImageResponse{ID: 1, ImageUrl: image.ImageUrl, AltText: image.AltText, Position: 1}, nil
+				}
+			},
+		},
+		{
+			title:              "attach product image failed invalid url",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/id/7/images",
+			body:               `{"image_url":"javascript:alert(1)"}`,
+			before: func() {
+				AttachProductImageHandler = func(image entity.ProductImage) (response dto.ProductImageResponse, err error) {
+					attached = image
+					return dto.ProductImageResponse{}, nil
+				}
+			},
+		},
+		{
+			title:              "attach product image failed gallery full",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/id/7/images",
+			body:               `{"image_url":"https://res.cloudinary.com/demo/image.png"}`,
+			before: func() {
+				AttachProductImageHandler = func(image entity.ProductImage) (response dto.ProductImageResponse, err error) {
+					attached = image
+					return dto.ProductImageResponse{}, entity.ErrTooManyImages
+				}
+			},
+		},
+		{
+			title:              "reorder product images success",
+			method:             fiber.MethodPut,
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/id/7/images/order",
+			body:               `{"image_ids":[3,1,2]}`,
+			before: func() {
+				ReorderProductImagesHandler = func(ids []int) (response []dto.ProductImageResponse, err error) {
+					reordered = ids
+					return []dto.ProductImageResponse{}, nil
+				}
+			},
+		},
+		{
+			title:              "reorder product images failed invalid order",
+			method:             fiber.MethodPut,
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/id/7/images/order",
+			body:               `{"image_ids":[1]}`,
+			before: func() {
+				ReorderProductImagesHandler = func(ids []int) (response []dto.ProductImageResponse, err error) {
+					reordered = ids
+					return nil, entity.ErrImageOrderIsInvalid
+				}
+			},
+		},
+		{
+			title:              "remove product image success",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/id/7/images/1",
+			before: func() {
+				RemoveProductImageHandler = func() (err error) {
+					return nil
+				}
+			},
+		},
+		{
+			title:              "remove product image failed image not found",
+			method:             fiber.MethodDelete,
+			expectedStatusCode: fiber.StatusNotFound,
+			endpoint:           "/v1/products/id/7/images/1",
+			before: func() {
+				RemoveProductImageHandler = func() (err error) {
+					return entity.ErrProductImageNotFound
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+			attached = entity.ProductImage{}
+			reordered = nil
+
+			test.before()
+
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+				ID:    "1",
+				Email: "user@gmail.com",
+				Role:  entity.RoleMerchant,
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+			})
+			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+			require.NoError(t, err)
+
+			mockService := mockProductService{}
+			handler := NewProductHandler(mockService)
+
+			router.Post("/v1/products/id/:product_id/images", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.AttachProductImage)
+			router.Put("/v1/products/id/:product_id/images/order", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.ReorderProductImages)
+			router.Delete("/v1/products/id/:product_id/images/:image_id", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.RemoveProductImage)
+
+			request := httptest.NewRequest(test.method, test.endpoint, bytes.NewBufferString(test.body))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+signedToken)
+
+			resp, _ := router.Test(request, 1)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.title == "attach product image success" {
+				require.Equal(t, 7, attached.ProductId)
+				require.Equal(t, "front", attached.AltText)
+			}
+
+			if test.title == "reorder product images success" {
+				require.Equal(t, []int{3, 1, 2}, reordered)
+			}
+		})
+	}
+}
+
+func TestImportExportProductHandler(t *testing.T) {
+	signToken := func(t *testing.T) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+			ID:    "1",
+			Email: "user@gmail.com",
+			Role:  entity.RoleMerchant,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		})
+		signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+		require.NoError(t, err)
+
+		return signedToken
+	}
+
+	newRouter := func() *fiber.App {
+		router := fiber.New()
+		handler := NewProductHandler(mockProductService{})
+
+		router.Post("/v1/products/import", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.ImportProducts)
+		router.Get("/v1/products/export", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.ExportProducts)
+
+		return router
+	}
+
+	t.Run("import product success", func(t *testing.T) {
+		var received string
+		ImportProductsHandler = func(file io.Reader) (response dto.ImportProductResponse, err error) {
+			content, err := io.ReadAll(file)
+			received = string(content)
+			return dto.ImportProductResponse{TotalRows: 1, Imported: 1}, err
+		}
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "products.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("name,description,price,stock,category,image_url\n"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		request := httptest.NewRequest(fiber.MethodPost, "/v1/products/import", body)
+		request.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+		request.Header.Set(fiber.HeaderAuthorization, "Bearer "+signToken(t))
+
+		resp, _ := newRouter().Test(request, 1)
+
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.Equal(t, "name,description,price,stock,category,image_url\n", received)
+	})
+
+	t.Run("import product failed file is required", func(t *testing.T) {
+		request := httptest.NewRequest(fiber.MethodPost, "/v1/products/import", nil)
+		request.Header.Set(fiber.HeaderAuthorization, "Bearer "+signToken(t))
+
+		resp, _ := newRouter().Test(request, 1)
+
+		require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("export product success", func(t *testing.T) {
+		ExportProductsHandler = func() (export func(ctx context.Context, w io.Writer) error, err error) {
+			return func(ctx context.Context, w io.Writer) error {
+				_, err := io.WriteString(w, "sku,name\nsku,shirt\n")
+				return err
+			}, nil
+		}
+
+		request := httptest.NewRequest(fiber.MethodGet, "/v1/products/export", nil)
+		request.Header.Set(fiber.HeaderAuthorization, "Bearer "+signToken(t))
+
+		resp, _ := newRouter().Test(request, 1)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/csv; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "sku,name\nsku,shirt\n", string(content))
+	})
+
+	t.Run("export product failed caller has no merchant", func(t *testing.T) {
+		ExportProductsHandler = func() (export func(ctx context.Context, w io.Writer) error, err error) {
+			return nil, entity.ErrMerchantNotFound
+		}
+
+		request := httptest.NewRequest(fiber.MethodGet, "/v1/products/export", nil)
+		request.Header.Set(fiber.HeaderAuthorization, "Bearer "+signToken(t))
+
+		resp, _ := newRouter().Test(request, 1)
+		require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestStockHandler(t *testing.T) {
+	type testCase struct {
+		title              string
+		method             string
+		expectedStatusCode int
+		endpoint           string
+		body               string
+		before             func()
+	}
+
+	var adjusted entity.InventoryMovement
+	var paged [2]int
+
+	var testCases = []testCase{
+		{
+			title:              "adjust stock success",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusCreated,
+			endpoint:           "/v1/products/id/3/stock/adjustments",
+			body:               `{"type":"adjustment","quantity":-2,"reason":"damaged in storage"}`,
+			before: func() {
+				AdjustStockHandler = func(movement entity.InventoryMovement) (response dto.InventoryMovementResponse, err error) {
+					adjusted = movement
+					return dto.InventoryMovementResponse{ID: 1, StockAfter: 8}, nil
+				}
+			},
+		},
+		{
+			title:              "adjust stock failed reason is required",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/id/3/stock/adjustments",
+			body:               `{"type":"adjustment","quantity":-2}`,
+			before:             func() {},
+		},
+		{
+			title:              "adjust stock failed insufficient stock",
+			method:             fiber.MethodPost,
+			expectedStatusCode: fiber.StatusConflict,
+			endpoint:           "/v1/products/id/3/stock/adjustments",
+			body:               `{"type":"adjustment","quantity":-200,"reason":"lost"}`,
+			before: func() {
+				AdjustStockHandler = func(movement entity.InventoryMovement) (response dto.InventoryMovementResponse, err error) {
+					return dto.InventoryMovementResponse{}, entity.ErrInsufficientStock
+				}
+			},
+		},
+		{
+			title:              "get stock movements success",
+			method:             fiber.MethodGet,
+			expectedStatusCode: fiber.StatusOK,
+			endpoint:           "/v1/products/id/3/stock/movements?limit=500&page=2",
+			before: func() {
+				GetStockMovementsHandler = func(limit, page int) (response []dto.InventoryMovementResponse, totalData int, err error) {
+					paged = [2]int{limit, page}
+					return []dto.InventoryMovementResponse{}, 0, nil
+				}
+			},
+		},
+		{
+			title:              "get stock movements failed invalid page",
+			method:             fiber.MethodGet,
+			expectedStatusCode: fiber.StatusBadRequest,
+			endpoint:           "/v1/products/id/3/stock/movements?page=-1",
+			before:             func() {},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+			adjusted = entity.InventoryMovement{}
+
+			test.before()
+
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+				ID:    "1",
+				Email: "user@gmail.com",
+				Role:  entity.RoleMerchant,
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+			})
+			signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+			require.NoError(t, err)
+
+			handler := NewProductHandler(mockProductService{})
+
+			router.Post("/v1/products/id/:product_id/stock/adjustments", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.AdjustStock)
+			router.Get("/v1/products/id/:product_id/stock/movements", middleware.AuthMiddleware(), middleware.RequireRole(entity.RoleMerchant), handler.GetStockMovements)
+
+			request := httptest.NewRequest(test.method, test.endpoint, bytes.NewBufferString(test.body))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+signedToken)
+
+			resp, _ := router.Test(request, 1)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.title == "adjust stock success" {
+				require.Equal(t, entity.InventoryMovement{
+					ProductId: 3,
+					Type:      entity.MovementAdjustment,
+					Quantity:  -2,
+					Reason:    "damaged in storage",
+					CreatedBy: "1",
+				}, adjusted)
+			}
+
+			if test.title == "get stock movements success" {
+				require.Equal(t, [2]int{entity.MaxLimit, 2}, paged)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/config"
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/ecommerce/infra/notifier"
+)
+
+const (
+	defaultLowStockInterval = 5 * time.Minute
+	lowStockBatchSize       = 100
+)
+
+type LowStockJob struct {
+	repository LowStockRepository
+	notifier   notifier.Notifier
+	interval   time.Duration
+}
+
+func NewLowStockJob(repository LowStockRepository, notifier notifier.Notifier, cfg config.Product) LowStockJob {
+	interval := time.Duration(cfg.LowStockIntervalMinute) * time.Minute
+	if interval <= 0 {
+		interval = defaultLowStockInterval
+	}
+
+	return LowStockJob{
+		repository: repository,
+		notifier:   notifier,
+		interval:   interval,
+	}
+}
+
+func (l LowStockJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := l.Check(ctx); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l LowStockJob) Check(ctx context.Context) (total int, err error) {
+	for {
+		notifications, err := l.repository.ClaimLowStock(ctx, lowStockBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, notification := range notifications {
+			if l.notifier == nil {
+				continue
+			}
+
+			err := l.notifier.Notify(ctx, notification.MerchantEmail, notification.Title, notification.Message)
+			if err != nil {
+				logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+			}
+		}
+
+		total += len(notifications)
+		if len(notifications) < lowStockBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelInfo, fmt.Sprintf("recorded %d low stock alerts", total))
+	}
+
+	return
+}
@@ -0,0 +1,110 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/config"
+	"github.com/ecommerce/entity"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLowStockRepository struct{}
+type mockNotifier struct{}
+
+// ClaimLowStock implements LowStockRepository.
+func (mockLowStockRepository) ClaimLowStock(ctx context.Context, limit int) (notifications []entity.Notification, err error) {
+	return ClaimLowStock(limit)
+}
+
+// Notify implements notifier.Notifier.
+func (mockNotifier) Notify(ctx context.Context, recipient, subject, message string) (err error) {
+	return NotifyMerchant(recipient, subject)
+}
+
+var (
+	ClaimLowStock  func(limit int) (notifications []entity.Notification, err error)
+	NotifyMerchant func(recipient, subject string) (err error)
+)
+
+func TestLowStockJob(t *testing.T) {
+	t.Run("success : claim batches until drained", func(t *testing.T) {
+		calls := 0
+		ClaimLowStock = func(limit int) (notifications []entity.Notification, err error) {
+			calls++
+			if calls == 1 {
+				return make([]entity.Notification, limit), nil
+			}
+
+			return []entity.Notification{{MerchantEmail: "merchant@example.com", Title: "Low stock: Kopi"}}, nil
+		}
+
+		delivered := 0
+		NotifyMerchant = func(recipient, subject string) (err error) {
+			delivered++
+			return nil
+		}
+
+		job := NewLowStockJob(mockLowStockRepository{}, mockNotifier{}, config.Product{})
+		total, err := job.Check(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, lowStockBatchSize+1, total)
+		require.Equal(t, 2, calls)
+		require.Equal(t, lowStockBatchSize+1, delivered)
+		require.Equal(t, defaultLowStockInterval, job.interval)
+	})
+
+	t.Run("success : delivery error does not fail the check", func(t *testing.T) {
+		ClaimLowStock = func(limit int) (notifications []entity.Notification, err error) {
+			return []entity.Notification{{MerchantEmail: "merchant@example.com"}}, nil
+		}
+
+		NotifyMerchant = func(recipient, subject string) (err error) {
+			return errors.New("smtp unavailable")
+		}
+
+		job := NewLowStockJob(mockLowStockRepository{}, mockNotifier{}, config.Product{LowStockIntervalMinute: 1})
+		total, err := job.Check(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+		require.Equal(t, time.Minute, job.interval)
+	})
+
+	t.Run("success : nil notifier only records alerts", func(t *testing.T) {
+		ClaimLowStock = func(limit int) (notifications []entity.Notification, err error) {
+			return []entity.Notification{{}}, nil
+		}
+
+		job := NewLowStockJob(mockLowStockRepository{}, nil, config.Product{})
+		total, err := job.Check(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+	})
+
+	t.Run("err : repository error", func(t *testing.T) {
+		ClaimLowStock = func(limit int) (notifications []entity.Notification, err error) {
+			return nil, errors.New("internal server error")
+		}
+
+		job := NewLowStockJob(mockLowStockRepository{}, mockNotifier{}, config.Product{})
+		_, err := job.Check(context.Background())
+		require.Equal(t, errors.New("internal server error"), err)
+	})
+
+	t.Run("success : run stops when context is done", func(t *testing.T) {
+		calls := 0
+		ClaimLowStock = func(limit int) (notifications []entity.Notification, err error) {
+			calls++
+			return nil, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		job := NewLowStockJob(mockLowStockRepository{}, mockNotifier{}, config.Product{})
+		job.Run(ctx)
+		require.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,66 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/config"
+	logs "github.com/ecommerce/infra/logger"
+)
+
+const defaultPurgeInterval = time.Hour
+
+type PurgeJob struct {
+	repository PurgeRepository
+	retention  time.Duration
+	interval   time.Duration
+}
+
+func NewPurgeJob(repository PurgeRepository, cfg config.Product) PurgeJob {
+	interval := time.Duration(cfg.PurgeIntervalMinute) * time.Minute
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	return PurgeJob{
+		repository: repository,
+		retention:  time.Duration(cfg.PurgeRetentionDay) * 24 * time.Hour,
+		interval:   interval,
+	}
+}
+
+func (p PurgeJob) Run(ctx context.Context) {
+	if p.retention <= 0 {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelInfo, "product purge job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Purge(ctx, time.Now()); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p PurgeJob) Purge(ctx context.Context, now time.Time) (total int64, err error) {
+	total, err = p.repository.Purge(ctx, now.Add(-p.retention))
+	if err != nil {
+		return
+	}
+
+	if total > 0 {
+		logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelInfo, fmt.Sprintf("purged %d soft deleted products", total))
+	}
+
+	return
+}
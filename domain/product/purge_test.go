@@ -0,0 +1,77 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/config"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPurgeRepository struct{}
+
+// Purge implements PurgeRepository.
+func (mockPurgeRepository) Purge(ctx context.Context, before time.Time) (total int64, err error) {
+	return PurgeProduct(before)
+}
+
+var PurgeProduct func(before time.Time) (total int64, err error)
+
+func TestPurgeJob(t *testing.T) {
+	now := time.Date(2023, 12, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success : purge rows older than retention", func(t *testing.T) {
+		var received time.Time
+		PurgeProduct = func(before time.Time) (total int64, err error) {
+			received = before
+			return 3, nil
+		}
+
+		job := NewPurgeJob(mockPurgeRepository{}, config.Product{PurgeRetentionDay: 30})
+		total, err := job.Purge(context.Background(), now)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), total)
+		require.Equal(t, time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC), received)
+		require.Equal(t, defaultPurgeInterval, job.interval)
+	})
+
+	t.Run("err : repository error", func(t *testing.T) {
+		PurgeProduct = func(before time.Time) (total int64, err error) {
+			return 0, errors.New("internal server error")
+		}
+
+		job := NewPurgeJob(mockPurgeRepository{}, config.Product{PurgeRetentionDay: 30, PurgeIntervalMinute: 5})
+		_, err := job.Purge(context.Background(), now)
+		require.Equal(t, errors.New("internal server error"), err)
+		require.Equal(t, 5*time.Minute, job.interval)
+	})
+
+	t.Run("success : zero retention disables the job", func(t *testing.T) {
+		called := false
+		PurgeProduct = func(before time.Time) (total int64, err error) {
+			called = true
+			return 0, nil
+		}
+
+		job := NewPurgeJob(mockPurgeRepository{}, config.Product{})
+		job.Run(context.Background())
+		require.False(t, called)
+	})
+
+	t.Run("success : run stops when context is done", func(t *testing.T) {
+		calls := 0
+		PurgeProduct = func(before time.Time) (total int64, err error) {
+			calls++
+			return 0, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		job := NewPurgeJob(mockPurgeRepository{}, config.Product{PurgeRetentionDay: 1})
+		job.Run(ctx)
+		require.Equal(t, 1, calls)
+	})
+}
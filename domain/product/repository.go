@@ -2,14 +2,36 @@ package product
 
 import (
 	"context"
+	"time"
 
 	"github.com/ecommerce/entity"
 )
 
 type Repository interface {
 	Create(ctx context.Context, product entity.Product) (err error)
+	CreateBatch(ctx context.Context, products []entity.Product) (err error)
+	ExportByMerchantId(ctx context.Context, merchantId int, fn func(product entity.Product) error) (err error)
 	GetByMerchantId(ctx context.Context, queryParam string, limit, page, merchantId int) (products []entity.Product, totalData int, err error)
-	GetById(ctx context.Context, id int) (product entity.Product, err error)
+	GetById(ctx context.Context, id, merchantId int) (product entity.Product, err error)
 	Update(ctx context.Context, product entity.Product) (err error)
 	GetBySku(ctx context.Context, sku string) (product entity.Product, err error)
+	GetCatalog(ctx context.Context, filter entity.CatalogFilter) (products []entity.Product, totalData int, err error)
+	Search(ctx context.Context, filter entity.CatalogFilter) (products []entity.Product, totalData int, err error)
+	Delete(ctx context.Context, id, merchantId int, deletedBy string) (err error)
+	Restore(ctx context.Context, id, merchantId int, updatedBy string) (err error)
+	GetImages(ctx context.Context, productId int) (images []entity.ProductImage, err error)
+	AttachImage(ctx context.Context, image entity.ProductImage, updatedBy string) (response entity.ProductImage, err error)
+	ReorderImages(ctx context.Context, productId int, ids []int, updatedBy string) (err error)
+	RemoveImage(ctx context.Context, productId, imageId int, updatedBy string) (err error)
+	RecordMovement(ctx context.Context, movement entity.InventoryMovement) (response entity.InventoryMovement, err error)
+	GetMovements(ctx context.Context, productId, limit, page int) (movements []entity.InventoryMovement, totalData int, err error)
+	GetVariants(ctx context.Context, productId int) (options []entity.ProductOption, variants []entity.ProductVariant, err error)
+}
+
+type PurgeRepository interface {
+	Purge(ctx context.Context, before time.Time) (total int64, err error)
+}
+
+type LowStockRepository interface {
+	ClaimLowStock(ctx context.Context, limit int) (notifications []entity.Notification, err error)
 }
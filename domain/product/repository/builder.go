@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryBuilder composes the dynamic tail of a product query. Conditions are
+// written with `?` placeholders which are numbered on Build, so user input is
+// only ever passed to the driver as a bound argument.
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+	bound      int
+	orderBy    string
+	limit      int
+	offset     int
+	paginate   bool
+}
+
+func newQueryBuilder() *queryBuilder {
+	return &queryBuilder{}
+}
+
+// Bind supplies args for placeholders written directly into the base query as
+// $1..$n. It must be called before Where, whose placeholders are numbered
+// after the bound ones.
+func (b *queryBuilder) Bind(args ...interface{}) *queryBuilder {
+	b.args = append(b.args, args...)
+	b.bound += len(args)
+	return b
+}
+
+func (b *queryBuilder) Where(condition string, args ...interface{}) *queryBuilder {
+	b.conditions = append(b.conditions, condition)
+	b.args = append(b.args, args...)
+	return b
+}
+
+func (b *queryBuilder) OrderBy(orderBy string) *queryBuilder {
+	b.orderBy = orderBy
+	return b
+}
+
+func (b *queryBuilder) Paginate(limit, page int) *queryBuilder {
+	if page < 1 {
+		page = 1
+	}
+
+	b.limit = limit
+	b.offset = (page - 1) * limit
+	b.paginate = true
+	return b
+}
+
+func (b *queryBuilder) Build(base string) (query string, args []interface{}) {
+	query, args = b.BuildCount(base)
+
+	if b.orderBy != "" {
+		query = fmt.Sprintf("%s ORDER BY %s", query, b.orderBy)
+	}
+
+	if b.paginate {
+		args = append(args, b.limit, b.offset)
+		query = fmt.Sprintf("%s LIMIT $%d OFFSET $%d", query, len(args)-1, len(args))
+	}
+
+	return
+}
+
+func (b *queryBuilder) BuildCount(base string) (query string, args []interface{}) {
+	args = append([]interface{}{}, b.args...)
+	query = base
+
+	if len(b.conditions) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, numberPlaceholders(strings.Join(b.conditions, " AND "), b.bound))
+	}
+
+	return
+}
+
+func numberPlaceholders(query string, n int) string {
+	var builder strings.Builder
+
+	for _, r := range query {
+		if r == '?' {
+			n++
+			builder.WriteString(fmt.Sprintf("$%d", n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/ecommerce/entity"
+	"github.com/stretchr/testify/require"
+)
+
+var hostileInputs = []string{
+	"'; DROP TABLE products; --",
+	"' OR '1'='1",
+	"%' UNION SELECT password FROM auth --",
+	"\\'; SELECT pg_sleep(10); --",
+	"$1 OR 1=1",
+	"? OR 1=1",
+	"100%_off",
+}
+
+func TestQueryBuilder(t *testing.T) {
+	t.Run("success : build where, order and pagination", func(t *testing.T) {
+		query, args := newQueryBuilder().
+			Where("p.merchant_id = ?", 1).
+			Where("p.price BETWEEN ? AND ?", 100, 200).
+			Where("p.stock > 0").
+			OrderBy("p.id ASC").
+			Paginate(10, 3).
+			Build("SELECT p.id FROM products p")
+
+		require.Equal(t, "SELECT p.id FROM products p WHERE p.merchant_id = $1 AND p.price BETWEEN $2 AND $3 AND p.stock > 0 ORDER BY p.id ASC LIMIT $4 OFFSET $5", query)
+		require.Equal(t, []interface{}{1, 100, 200, 10, 20}, args)
+	})
+
+	t.Run("success : count query has no order or pagination", func(t *testing.T) {
+		builder := newQueryBuilder().
+			Where("p.merchant_id = ?", 1).
+			OrderBy("p.id ASC").
+			Paginate(10, 3)
+
+		query, args := builder.BuildCount("SELECT COUNT(p.id) FROM products p")
+		require.Equal(t, "SELECT COUNT(p.id) FROM products p WHERE p.merchant_id = $1", query)
+		require.Equal(t, []interface{}{1}, args)
+
+		// building the count first must not leak args into the list query
+		_, args = builder.Build("SELECT p.id FROM products p")
+		require.Equal(t, []interface{}{1, 10, 20}, args)
+	})
+
+	t.Run("success : conditions are numbered after bound args", func(t *testing.T) {
+		query, args := newQueryBuilder().
+			Bind("shoes").
+			Where("p.name ILIKE $1").
+			Where("p.category_id = ?", 2).
+			Paginate(10, 1).
+			Build("SELECT p.id, similarity(p.name, $1) FROM products p")
+
+		require.Equal(t, "SELECT p.id, similarity(p.name, $1) FROM products p WHERE p.name ILIKE $1 AND p.category_id = $2 LIMIT $3 OFFSET $4", query)
+		require.Equal(t, []interface{}{"shoes", 2, 10, 0}, args)
+	})
+
+	t.Run("success : no conditions", func(t *testing.T) {
+		query, args := newQueryBuilder().Build("SELECT p.id FROM products p")
+		require.Equal(t, "SELECT p.id FROM products p", query)
+		require.Empty(t, args)
+	})
+
+	t.Run("success : page below one falls back to the first page", func(t *testing.T) {
+		_, args := newQueryBuilder().Paginate(10, 0).Build("SELECT p.id FROM products p")
+		require.Equal(t, []interface{}{10, 0}, args)
+	})
+}
+
+func TestEscapeLike(t *testing.T) {
+	require.Equal(t, `100\%\_off`, escapeLike("100%_off"))
+	require.Equal(t, `a\\b`, escapeLike(`a\b`))
+	require.Equal(t, "shoes", escapeLike("shoes"))
+}
+
+func TestMerchantProductsQueryHostileInput(t *testing.T) {
+	for _, input := range hostileInputs {
+		t.Run(input, func(t *testing.T) {
+			builder := merchantProductsQuery(input, 7).OrderBy("p.id ASC").Paginate(10, 2)
+
+			query, args := builder.Build(queryGetByMerchantId)
+			require.NotContains(t, query, input)
+			require.Equal(t, []interface{}{7, escapeLike(input), 10, 10}, args)
+
+			queryCount, args := builder.BuildCount(queryCountByMerchantId)
+			require.NotContains(t, queryCount, input)
+			require.NotContains(t, queryCount, "LIMIT")
+			require.NotContains(t, queryCount, "OFFSET")
+			require.Equal(t, []interface{}{7, escapeLike(input)}, args)
+		})
+	}
+}
+
+func TestCatalogQueryHostileInput(t *testing.T) {
+	for _, input := range hostileInputs {
+		t.Run(input, func(t *testing.T) {
+			filter := entity.CatalogFilter{
+				Query:   input,
+				City:    input,
+				InStock: true,
+				Sort:    entity.SortPriceAsc,
+				Limit:   10,
+				Page:    1,
+			}
+			builder := catalogQuery(filter).OrderBy(catalogSorts[filter.Sort]).Paginate(filter.Limit, filter.Page)
+
+			query, args := builder.Build(queryGetCatalog)
+			require.NotContains(t, query, input)
+			require.Contains(t, query, "ORDER BY p.price ASC, p.id ASC LIMIT $3 OFFSET $4")
+			require.Equal(t, []interface{}{escapeLike(input), input, 10, 0}, args)
+
+			queryCount, args := builder.BuildCount(queryCountCatalog)
+			require.NotContains(t, queryCount, input)
+			require.NotContains(t, queryCount, "LIMIT")
+			require.Equal(t, []interface{}{escapeLike(input), input}, args)
+		})
+	}
+}
+
+func TestCatalogQueryFilters(t *testing.T) {
+	filter := entity.CatalogFilter{
+		CategoryId: 2,
+		MinPrice:   1000,
+		MaxPrice:   5000,
+		Sort:       entity.SortNewest,
+		Limit:      10,
+		Page:       1,
+	}
+
+	query, args := catalogQuery(filter).BuildCount(queryCountCatalog)
+	require.Contains(t, query, "WHERE p.deleted_at IS NULL AND p.category_id = $1 AND p.price >= $2 AND p.price <= $3")
+	require.NotContains(t, query, "p.stock > 0")
+	require.Equal(t, []interface{}{2, 1000, 5000}, args)
+}
+
+func TestSearchQueryHostileInput(t *testing.T) {
+	for _, input := range hostileInputs {
+		t.Run(input, func(t *testing.T) {
+			filter := entity.CatalogFilter{
+				Query:      input,
+				CategoryId: 3,
+				Sort:       entity.SortRelevance,
+				Limit:      10,
+				Page:       2,
+			}
+			builder := searchQuery(filter).OrderBy(catalogSorts[filter.Sort]).Paginate(filter.Limit, filter.Page)
+
+			query, args := builder.Build(querySearch)
+			require.NotContains(t, query, input)
+			require.Contains(t, query, "p.search_vector @@ websearch_to_tsquery('simple', $1) OR $1 <% p.name")
+			require.Contains(t, query, "p.category_id = $2 ORDER BY rank DESC, p.id DESC LIMIT $3 OFFSET $4")
+			require.Equal(t, []interface{}{input, 3, 10, 10}, args)
+
+			queryCount, args := builder.BuildCount(queryCountSearch)
+			require.NotContains(t, queryCount, input)
+			require.NotContains(t, queryCount, "LIMIT")
+			require.Equal(t, []interface{}{input, 3}, args)
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/ecommerce/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ProductRepository struct {
@@ -20,46 +22,101 @@ func NewProductRepository(db *sqlx.DB) ProductRepository {
 }
 
 func (p ProductRepository) Create(ctx context.Context, product entity.Product) (err error) {
-	stmt, err := p.db.PrepareNamedContext(ctx, queryCreate)
+	return p.CreateBatch(ctx, []entity.Product{product})
+}
+
+func (p ProductRepository) CreateBatch(ctx context.Context, products []entity.Product) (err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareNamedContext(ctx, queryCreate)
 	if err != nil {
 		return
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, product)
+	for _, product := range products {
+		if len(product.Variants) > 0 {
+			product.Stock = 0
+		}
+
+		err = stmt.GetContext(ctx, &product.ID, product)
+		if err != nil {
+			return
+		}
+
+		_, err = tx.ExecContext(ctx, queryCreatePrimaryImage, product.ID, product.ImageUrl)
+		if err != nil {
+			return
+		}
+
+		if product.Stock != 0 {
+			_, err = tx.ExecContext(ctx, queryInsertMovement, product.ID, nil, entity.MovementRestock, product.Stock, product.Stock, "initial stock", product.CreatedBy)
+			if err != nil {
+				return
+			}
+		}
+
+		if len(product.Variants) > 0 {
+			err = replaceVariants(ctx, tx, product, entity.InventoryMovement{
+				Type:      entity.MovementRestock,
+				Reason:    "initial stock",
+				CreatedBy: product.CreatedBy,
+			})
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p ProductRepository) ExportByMerchantId(ctx context.Context, merchantId int, fn func(product entity.Product) error) (err error) {
+	rows, err := p.db.QueryxContext(ctx, queryExportByMerchantId, merchantId)
 	if err != nil {
 		return
 	}
+	defer rows.Close()
 
-	return
+	for rows.Next() {
+		var product entity.Product
+		if err = rows.StructScan(&product); err != nil {
+			return
+		}
+
+		if err = fn(product); err != nil {
+			return
+		}
+	}
+
+	return rows.Err()
 }
 
 func (p ProductRepository) GetByMerchantId(ctx context.Context, queryParam string, limit, page, merchantId int) (products []entity.Product, totalData int, err error) {
-	offset := (page - 1) * limit
-	filter := mappingQueryFilter(queryParam)
-	queryLimitOffset := fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
-	query := fmt.Sprintf("%s %s %s", queryGetByMerchantId, filter, queryLimitOffset)
-	queryCount := fmt.Sprintf("%s %s %s", queryCountByMerchantId, filter, queryLimitOffset)
+	builder := merchantProductsQuery(queryParam, merchantId).OrderBy("p.id ASC").Paginate(limit, page)
 
-	err = p.db.SelectContext(ctx, &products, query, merchantId)
+	query, args := builder.Build(queryGetByMerchantId)
+	products = []entity.Product{}
+	err = p.db.SelectContext(ctx, &products, query, args...)
 	if err != nil {
 		return
 	}
 
-	err = p.db.GetContext(ctx, &totalData, queryCount, merchantId)
+	queryCount, args := builder.BuildCount(queryCountByMerchantId)
+	err = p.db.GetContext(ctx, &totalData, queryCount, args...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			totalData = 0
-			return []entity.Product{}, totalData, nil
-		}
 		return
 	}
 
 	return
 }
 
-func (p ProductRepository) GetById(ctx context.Context, id int) (product entity.Product, err error) {
-	err = p.db.GetContext(ctx, &product, queryGetById, id)
+func (p ProductRepository) GetById(ctx context.Context, id, merchantId int) (product entity.Product, err error) {
+	err = p.db.GetContext(ctx, &product, queryGetById, id, merchantId)
 	if err != nil {
 		return
 	}
@@ -68,17 +125,394 @@ func (p ProductRepository) GetById(ctx context.Context, id int) (product entity.
 }
 
 func (p ProductRepository) Update(ctx context.Context, product entity.Product) (err error) {
-	stmt, err := p.db.PrepareNamedContext(ctx, queryUpdate)
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareNamedContext(ctx, queryUpdate)
 	if err != nil {
 		return
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, product)
+	result, err := stmt.ExecContext(ctx, product)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.ExecContext(ctx, queryUpdatePrimaryImage, product.ID, product.ImageUrl)
+	if err != nil {
+		return
+	}
+
+	if product.ReplaceVariants {
+		err = replaceVariants(ctx, tx, product, entity.InventoryMovement{
+			Type:      entity.MovementAdjustment,
+			Reason:    "stock set by product update",
+			CreatedBy: updatedBy(product),
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	err = setStock(ctx, tx, product)
+	if err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+// setStock records the difference between the requested and the current
+// stock as an adjustment, the stock itself is only ever moved by the ledger.
+func setStock(ctx context.Context, tx *sqlx.Tx, product entity.Product) (err error) {
+	var current int
+	err = tx.GetContext(ctx, &current, queryLockStock, product.ID)
+	if err != nil {
+		return
+	}
+
+	if product.Stock == current {
+		return
+	}
+
+	_, err = applyMovement(ctx, tx, entity.InventoryMovement{
+		ProductId: product.ID,
+		Type:      entity.MovementAdjustment,
+		Quantity:  product.Stock - current,
+		Reason:    "stock set by product update",
+		CreatedBy: updatedBy(product),
+	})
+
+	return
+}
+
+func updatedBy(product entity.Product) string {
+	if product.UpdatedBy == nil {
+		return ""
+	}
+
+	return *product.UpdatedBy
+}
+
+// applyMovement moves the stock of the product and then of the variant when
+// one is given, checkout locks them in the same order.
+func applyMovement(ctx context.Context, tx *sqlx.Tx, movement entity.InventoryMovement) (response entity.InventoryMovement, err error) {
+	err = tx.GetContext(ctx, &movement.StockAfter, queryApplyProductStock, movement.ProductId, movement.Quantity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = entity.ErrInsufficientStock
+		}
+		return
+	}
+
+	if movement.VariantId != nil {
+		var id int
+		err = tx.GetContext(ctx, &id, queryApplyVariantStock, *movement.VariantId, movement.ProductId, movement.Quantity)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err = entity.ErrInsufficientStock
+			}
+			return
+		}
+	}
+
+	err = tx.GetContext(ctx, &response, queryInsertMovement, movement.ProductId, movement.VariantId, movement.Type,
+		movement.Quantity, movement.StockAfter, movement.Reason, movement.CreatedBy)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (p ProductRepository) RecordMovement(ctx context.Context, movement entity.InventoryMovement) (response entity.InventoryMovement, err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	response, err = applyMovement(ctx, tx, movement)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, queryTouchProduct, movement.ProductId, movement.CreatedBy)
+	if err != nil {
+		return
+	}
+
+	return response, tx.Commit()
+}
+
+func (p ProductRepository) GetMovements(ctx context.Context, productId, limit, page int) (movements []entity.InventoryMovement, totalData int, err error) {
+	movements = []entity.InventoryMovement{}
+	err = p.db.SelectContext(ctx, &movements, queryGetMovements, productId, limit, (page-1)*limit)
+	if err != nil {
+		return
+	}
+
+	err = p.db.GetContext(ctx, &totalData, queryCountMovements, productId)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (p ProductRepository) GetImages(ctx context.Context, productId int) (images []entity.ProductImage, err error) {
+	images = []entity.ProductImage{}
+	err = p.db.SelectContext(ctx, &images, queryGetImages, productId)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (p ProductRepository) AttachImage(ctx context.Context, image entity.ProductImage, updatedBy string) (response entity.ProductImage, err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &response, queryAttachImage, image.ProductId, image.ImageUrl, image.AltText)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, querySyncPrimaryImage, image.ProductId, updatedBy)
+	if err != nil {
+		return
+	}
+
+	return response, tx.Commit()
+}
+
+func (p ProductRepository) ReorderImages(ctx context.Context, productId int, ids []int, updatedBy string) (err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	for position, id := range ids {
+		_, err = tx.ExecContext(ctx, queryUpdateImagePosition, position, id, productId)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, querySyncPrimaryImage, productId, updatedBy)
+	if err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+func (p ProductRepository) RemoveImage(ctx context.Context, productId, imageId int, updatedBy string) (err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, queryRemoveImage, imageId, productId)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return
 	}
 
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.ExecContext(ctx, queryRenumberImages, productId)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, querySyncPrimaryImage, productId, updatedBy)
+	if err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+func (p ProductRepository) GetVariants(ctx context.Context, productId int) (options []entity.ProductOption, variants []entity.ProductVariant, err error) {
+	options = []entity.ProductOption{}
+	err = p.db.SelectContext(ctx, &options, queryGetOptions, productId)
+	if err != nil {
+		return
+	}
+
+	variants = []entity.ProductVariant{}
+	err = p.db.SelectContext(ctx, &variants, queryGetVariants, productId)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func replaceVariants(ctx context.Context, tx *sqlx.Tx, product entity.Product, movement entity.InventoryMovement) (err error) {
+	_, err = tx.ExecContext(ctx, queryDeleteOptions, product.ID)
+	if err != nil {
+		return
+	}
+
+	for _, option := range product.Options {
+		_, err = tx.ExecContext(ctx, queryInsertOption, product.ID, option.Name, option.Values, option.Position)
+		if err != nil {
+			return
+		}
+	}
+
+	ids := []int64{}
+	for _, variant := range product.Variants {
+		var current entity.ProductVariant
+		err = tx.GetContext(ctx, &current, queryUpsertVariant, product.ID, variant.Sku, variant.Options, variant.Price, variant.ImageUrl)
+		if err != nil {
+			return
+		}
+		ids = append(ids, int64(current.ID))
+
+		err = moveVariantStock(ctx, tx, product.ID, current, variant.Stock, movement)
+		if err != nil {
+			return
+		}
+	}
+
+	stale := []entity.ProductVariant{}
+	err = tx.SelectContext(ctx, &stale, queryLockStaleVariants, product.ID, pq.Array(ids))
+	if err != nil {
+		return
+	}
+
+	for _, variant := range stale {
+		removal := movement
+		removal.Reason = fmt.Sprintf("variant %s removed by product update", variant.Sku)
+
+		err = moveVariantStock(ctx, tx, product.ID, variant, 0, removal)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, queryDeleteStaleVariants, product.ID, pq.Array(ids))
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func moveVariantStock(ctx context.Context, tx *sqlx.Tx, productId int, variant entity.ProductVariant, stock int, movement entity.InventoryMovement) (err error) {
+	if stock == variant.Stock {
+		return
+	}
+
+	variantId := variant.ID
+	movement.ProductId = productId
+	movement.VariantId = &variantId
+	movement.Quantity = stock - variant.Stock
+
+	_, err = applyMovement(ctx, tx, movement)
+
+	return
+}
+
+func (p ProductRepository) Delete(ctx context.Context, id, merchantId int, deletedBy string) (err error) {
+	return p.execScoped(ctx, queryDelete, deletedBy, id, merchantId)
+}
+
+func (p ProductRepository) Restore(ctx context.Context, id, merchantId int, updatedBy string) (err error) {
+	return p.execScoped(ctx, queryRestore, updatedBy, id, merchantId)
+}
+
+func (p ProductRepository) Purge(ctx context.Context, before time.Time) (total int64, err error) {
+	result, err := p.db.ExecContext(ctx, queryPurge, before)
+	if err != nil {
+		return
+	}
+
+	return result.RowsAffected()
+}
+
+func (p ProductRepository) ClaimLowStock(ctx context.Context, limit int) (notifications []entity.Notification, err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, queryResetLowStock)
+	if err != nil {
+		return
+	}
+
+	claimed := []entity.Notification{}
+	err = tx.SelectContext(ctx, &claimed, queryClaimLowStock, limit)
+	if err != nil {
+		return
+	}
+
+	notifications = []entity.Notification{}
+	for _, notification := range claimed {
+		notification = entity.NewNotification().LowStock(notification)
+
+		err = tx.GetContext(ctx, &notification, queryInsertNotification, notification.MerchantId, notification.ProductId,
+			notification.Type, notification.Title, notification.Message, notification.Stock, notification.Threshold)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, queryMarkLowStock, notification.ProductId)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, tx.Commit()
+}
+
+func (p ProductRepository) execScoped(ctx context.Context, query string, args ...interface{}) (err error) {
+	result, err := p.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
 	return
 }
 
@@ -91,12 +525,113 @@ func (p ProductRepository) GetBySku(ctx context.Context, sku string) (product en
 	return
 }
 
-func mappingQueryFilter(queryParam string) string {
-	filter := ""
+func (p ProductRepository) GetSellableBySku(ctx context.Context, sku string) (product entity.Product, err error) {
+	err = p.db.GetContext(ctx, &product, queryGetSellableBySku, sku)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (p ProductRepository) GetCatalog(ctx context.Context, filter entity.CatalogFilter) (products []entity.Product, totalData int, err error) {
+	builder := catalogQuery(filter).OrderBy(catalogSorts[filter.Sort]).Paginate(filter.Limit, filter.Page)
+
+	queryCount, args := builder.BuildCount(queryCountCatalog)
+	err = p.db.GetContext(ctx, &totalData, queryCount, args...)
+	if err != nil {
+		return
+	}
+
+	query, args := builder.Build(queryGetCatalog)
+	products = []entity.Product{}
+	err = p.db.SelectContext(ctx, &products, query, args...)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (p ProductRepository) Search(ctx context.Context, filter entity.CatalogFilter) (products []entity.Product, totalData int, err error) {
+	builder := searchQuery(filter).OrderBy(catalogSorts[entity.SortRelevance]).Paginate(filter.Limit, filter.Page)
+
+	queryCount, args := builder.BuildCount(queryCountSearch)
+	err = p.db.GetContext(ctx, &totalData, queryCount, args...)
+	if err != nil {
+		return
+	}
+
+	query, args := builder.Build(querySearch)
+	products = []entity.Product{}
+	err = p.db.SelectContext(ctx, &products, query, args...)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+var catalogSorts = map[string]string{
+	entity.SortNewest:    "p.created_at DESC, p.id DESC",
+	entity.SortPriceAsc:  "p.price ASC, p.id ASC",
+	entity.SortPriceDesc: "p.price DESC, p.id DESC",
+	entity.SortNameAsc:   "p.name ASC, p.id ASC",
+	entity.SortNameDesc:  "p.name DESC, p.id DESC",
+	entity.SortRelevance: "rank DESC, p.id DESC",
+}
+
+func merchantProductsQuery(queryParam string, merchantId int) *queryBuilder {
+	builder := newQueryBuilder().
+		Where("p.merchant_id = ?", merchantId).
+		Where("p.deleted_at IS NULL")
 
 	if queryParam != "" {
-		filter = fmt.Sprintf("%s AND p.name ILIKE '%%%s%%'", filter, queryParam)
+		builder.Where("p.name ILIKE '%' || ? || '%'", escapeLike(queryParam))
+	}
+
+	return builder
+}
+
+func catalogQuery(filter entity.CatalogFilter) *queryBuilder {
+	builder := newQueryBuilder().Where("p.deleted_at IS NULL")
+
+	if filter.Query != "" {
+		builder.Where("p.name ILIKE '%' || ? || '%'", escapeLike(filter.Query))
+	}
+
+	return applyCatalogFilters(builder, filter)
+}
+
+func searchQuery(filter entity.CatalogFilter) *queryBuilder {
+	builder := newQueryBuilder().
+		Bind(filter.Query).
+		Where("p.deleted_at IS NULL").
+		Where("(p.search_vector @@ websearch_to_tsquery('simple', $1) OR $1 <% p.name)")
+
+	return applyCatalogFilters(builder, filter)
+}
+
+func applyCatalogFilters(builder *queryBuilder, filter entity.CatalogFilter) *queryBuilder {
+	if filter.CategoryId > 0 {
+		builder.Where("p.category_id = ?", filter.CategoryId)
+	}
+
+	if filter.MinPrice > 0 {
+		builder.Where("p.price >= ?", filter.MinPrice)
+	}
+
+	if filter.MaxPrice > 0 {
+		builder.Where("p.price <= ?", filter.MaxPrice)
+	}
+
+	if filter.City != "" {
+		builder.Where("LOWER(m.city) = LOWER(?)", filter.City)
+	}
+
+	if filter.InStock {
+		builder.Where("p.stock > 0")
 	}
 
-	return filter
+	return builder
 }
@@ -11,8 +11,10 @@ const (
 		merchant_id, 
 		image_url, 
 		sku,
+		low_stock_threshold,
 		created_by
-	) VALUES (:name, :description, :price, :stock, :category_id, :merchant_id, :image_url, :sku, :created_by)
+	) VALUES (:name, :description, :price, :stock, :category_id, :merchant_id, :image_url, :sku, :low_stock_threshold, :created_by)
+	RETURNING id
 	`
 
 	queryGetByMerchantId = `
@@ -27,14 +29,12 @@ const (
 		p.image_url
 	FROM products p
 	JOIN categories c ON c.id = p.category_id
-	WHERE p.merchant_id = $1
 	`
 
 	queryCountByMerchantId = `
 	SELECT COUNT(p.id) as total_data 
 	FROM products p
 	JOIN categories c ON c.id = p.category_id
-	WHERE p.merchant_id = $1
 	`
 
 	queryGetById = `
@@ -48,11 +48,164 @@ const (
 		c.name as category,
 		p.category_id,
 		p.image_url,
+		p.low_stock_threshold,
+		p.version,
+		(SELECT COUNT(v.id) FROM product_variants v WHERE v.product_id = p.id) as variant_count,
 		p.created_at,
 		p.updated_at
 	FROM products p
 	JOIN categories c ON c.id = p.category_id
-	WHERE p.id = $1
+	WHERE p.id = $1 AND p.merchant_id = $2 AND p.deleted_at IS NULL
+	`
+
+	queryExportByMerchantId = `
+	SELECT
+		p.sku,
+		p.name,
+		p.description,
+		p.price,
+		p.stock,
+		c.name as category,
+		p.image_url
+	FROM products p
+	JOIN categories c ON c.id = p.category_id
+	WHERE p.merchant_id = $1 AND p.deleted_at IS NULL
+	ORDER BY p.id ASC
+	`
+
+	queryInsertMovement = `
+	INSERT INTO inventory_movements (product_id, variant_id, type, quantity, stock_after, reason, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, product_id, variant_id, type, quantity, stock_after, reason, created_by, created_at
+	`
+
+	// the guard keeps stock from going negative, no row means not enough stock
+	queryApplyProductStock = `
+	UPDATE products SET stock = stock + $2 WHERE id = $1 AND stock + $2 >= 0 RETURNING stock
+	`
+
+	queryApplyVariantStock = `
+	UPDATE product_variants SET
+		stock = stock + $3,
+		updated_at = NOW()
+	WHERE id = $1 AND product_id = $2 AND stock + $3 >= 0
+	RETURNING id
+	`
+
+	queryLockStock = `
+	SELECT stock FROM products WHERE id = $1 FOR UPDATE
+	`
+
+	queryTouchProduct = `
+	UPDATE products SET
+		updated_by = $2,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1
+	`
+
+	queryGetMovements = `
+	SELECT id, product_id, variant_id, type, quantity, stock_after, reason, created_by, created_at
+	FROM inventory_movements
+	WHERE product_id = $1
+	ORDER BY id DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	queryCountMovements = `
+	SELECT COUNT(id) as total_data FROM inventory_movements WHERE product_id = $1
+	`
+
+	queryGetImages = `
+	SELECT id, product_id, image_url, alt_text, position, created_at
+	FROM product_images
+	WHERE product_id = $1
+	ORDER BY position ASC, id ASC
+	`
+
+	queryAttachImage = `
+	INSERT INTO product_images (product_id, image_url, alt_text, position)
+	VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1))
+	RETURNING id, product_id, image_url, alt_text, position, created_at
+	`
+
+	queryCreatePrimaryImage = `
+	INSERT INTO product_images (product_id, image_url, position) VALUES ($1, $2, 0)
+	`
+
+	queryUpdatePrimaryImage = `
+	UPDATE product_images SET image_url = $2
+	WHERE id = (SELECT id FROM product_images WHERE product_id = $1 ORDER BY position ASC, id ASC LIMIT 1)
+	`
+
+	queryUpdateImagePosition = `
+	UPDATE product_images SET position = $1 WHERE id = $2 AND product_id = $3
+	`
+
+	queryRemoveImage = `
+	DELETE FROM product_images WHERE id = $1 AND product_id = $2
+	`
+
+	queryRenumberImages = `
+	UPDATE product_images i SET position = o.position
+	FROM (
+		SELECT id, ROW_NUMBER() OVER (ORDER BY position ASC, id ASC) - 1 AS position
+		FROM product_images
+		WHERE product_id = $1
+	) o
+	WHERE i.id = o.id
+	`
+
+	querySyncPrimaryImage = `
+	UPDATE products SET
+		image_url = COALESCE((SELECT image_url FROM product_images WHERE product_id = $1 ORDER BY position ASC, id ASC LIMIT 1), image_url),
+		updated_by = $2,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = $1
+	`
+
+	queryGetOptions = `
+	SELECT id, product_id, name, option_values, position
+	FROM product_options
+	WHERE product_id = $1
+	ORDER BY position ASC
+	`
+
+	queryGetVariants = `
+	SELECT id, product_id, sku, options, price, stock, image_url
+	FROM product_variants
+	WHERE product_id = $1
+	ORDER BY id ASC
+	`
+
+	queryDeleteOptions = `
+	DELETE FROM product_options WHERE product_id = $1
+	`
+
+	queryInsertOption = `
+	INSERT INTO product_options (product_id, name, option_values, position)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	queryUpsertVariant = `
+	INSERT INTO product_variants (product_id, sku, options, price, stock, image_url)
+	VALUES ($1, $2, $3, $4, 0, $5)
+	ON CONFLICT (product_id, options) DO UPDATE SET
+		price = EXCLUDED.price,
+		image_url = EXCLUDED.image_url,
+		updated_at = NOW()
+	RETURNING id, sku, stock
+	`
+
+	queryLockStaleVariants = `
+	SELECT id, sku, stock FROM product_variants
+	WHERE product_id = $1 AND NOT (id = ANY($2)) AND stock > 0
+	FOR UPDATE
+	`
+
+	queryDeleteStaleVariants = `
+	DELETE FROM product_variants WHERE product_id = $1 AND NOT (id = ANY($2))
 	`
 
 	queryUpdate = `
@@ -60,11 +213,73 @@ const (
 		name = :name, 
 		description = :description, 
 		price = :price, 
-		stock = :stock, 
 		category_id = :category_id, 
 		image_url = :image_url, 
-		updated_at = NOW() 
-	WHERE id = :id
+		low_stock_threshold = :low_stock_threshold,
+		updated_by = :updated_by,
+		updated_at = NOW(),
+		version = version + 1
+	WHERE id = :id AND merchant_id = :merchant_id AND version = :version AND deleted_at IS NULL
+	`
+
+	queryDelete = `
+	UPDATE products SET
+		deleted_by = $1,
+		deleted_at = NOW()
+	WHERE id = $2 AND merchant_id = $3 AND deleted_at IS NULL
+	`
+
+	queryRestore = `
+	UPDATE products SET
+		deleted_by = NULL,
+		deleted_at = NULL,
+		updated_by = $1,
+		updated_at = NOW()
+	WHERE id = $2 AND merchant_id = $3 AND deleted_at IS NOT NULL
+	`
+
+	// products referenced by an order are kept so order history stays intact
+	queryPurge = `
+	DELETE FROM products p
+	WHERE p.deleted_at IS NOT NULL AND p.deleted_at < $1
+	AND NOT EXISTS (SELECT 1 FROM order_details od WHERE od.product_id = p.id)
+	`
+
+	// a product alerts once per dip below its threshold, climbing back to the
+	// threshold re-arms it
+	queryResetLowStock = `
+	UPDATE products SET low_stock_alerted_at = NULL
+	WHERE low_stock_alerted_at IS NOT NULL AND stock >= low_stock_threshold
+	`
+
+	queryClaimLowStock = `
+	SELECT
+		p.id as product_id,
+		p.name as product_name,
+		p.stock,
+		p.low_stock_threshold as threshold,
+		p.merchant_id,
+		a.email as merchant_email
+	FROM products p
+	JOIN merchants m ON m.id = p.merchant_id
+	JOIN auth a ON a.id = m.created_by
+	WHERE p.deleted_at IS NULL
+	AND p.low_stock_threshold > 0
+	AND p.stock < p.low_stock_threshold
+	AND p.low_stock_alerted_at IS NULL
+	ORDER BY p.id ASC
+	LIMIT $1
+	FOR UPDATE OF p SKIP LOCKED
+	`
+
+	queryMarkLowStock = `
+	UPDATE products SET low_stock_alerted_at = NOW() WHERE id = $1
+	`
+
+	queryInsertNotification = `
+	INSERT INTO notifications (merchant_id, product_id, type, title, message, stock, threshold)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, created_at
 	`
 
 	queryGetBySku = `
@@ -82,10 +297,103 @@ const (
 		p.created_at,
 		p.updated_at,
 		m.name as merchant_name,
+		m.city as merchant_city,
+		(SELECT COUNT(v.id) FROM product_variants v WHERE v.product_id = p.id) as variant_count
+	FROM products p
+	JOIN categories c ON c.id = p.category_id
+	JOIN merchants m ON m.id = p.merchant_id
+	WHERE p.sku = $1 AND p.deleted_at IS NULL
+	`
+
+	queryGetSellableBySku = `
+	SELECT
+		p.id,
+		p.sku,
+		p.name,
+		p.price,
+		p.stock,
+		p.merchant_id,
+		p.image_url,
+		m.name as merchant_name,
+		m.city as merchant_city,
+		(SELECT COUNT(v.id) FROM product_variants v WHERE v.product_id = p.id) as variant_count,
+		NULL::integer as variant_id
+	FROM products p
+	JOIN merchants m ON m.id = p.merchant_id
+	WHERE p.sku = $1 AND p.deleted_at IS NULL
+	UNION ALL
+	SELECT
+		p.id,
+		v.sku,
+		p.name,
+		COALESCE(v.price, p.price) as price,
+		v.stock,
+		p.merchant_id,
+		COALESCE(v.image_url, p.image_url) as image_url,
+		m.name as merchant_name,
+		m.city as merchant_city,
+		0 as variant_count,
+		v.id as variant_id
+	FROM product_variants v
+	JOIN products p ON p.id = v.product_id
+	JOIN merchants m ON m.id = p.merchant_id
+	WHERE v.sku = $1 AND p.deleted_at IS NULL
+	`
+
+	queryGetCatalog = `
+	SELECT
+		p.id,
+		p.sku,
+		p.name,
+		p.price,
+		p.stock,
+		c.name as category,
+		p.category_id,
+		p.merchant_id,
+		p.image_url,
+		p.created_at,
+		m.name as merchant_name,
 		m.city as merchant_city
 	FROM products p
 	JOIN categories c ON c.id = p.category_id
 	JOIN merchants m ON m.id = p.merchant_id
-	WHERE p.sku = $1
+	`
+
+	queryCountCatalog = `
+	SELECT COUNT(p.id) as total_data
+	FROM products p
+	JOIN categories c ON c.id = p.category_id
+	JOIN merchants m ON m.id = p.merchant_id
+	`
+
+	// $1 is the search term, bound ahead of the filter conditions
+	querySearch = `
+	SELECT
+		p.id,
+		p.sku,
+		p.name,
+		p.price,
+		p.stock,
+		c.name as category,
+		p.category_id,
+		p.merchant_id,
+		p.image_url,
+		m.name as merchant_name,
+		m.city as merchant_city,
+		ts_rank_cd(p.search_vector, websearch_to_tsquery('simple', $1)) + word_similarity($1, p.name) as rank,
+		ts_headline('simple', translate(p.name, chr(2) || chr(3), ''), websearch_to_tsquery('simple', $1),
+			'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', HighlightAll=true') as name_highlight,
+		ts_headline('simple', translate(p.description, chr(2) || chr(3), ''), websearch_to_tsquery('simple', $1),
+			'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxWords=30, MinWords=10') as snippet
+	FROM products p
+	JOIN categories c ON c.id = p.category_id
+	JOIN merchants m ON m.id = p.merchant_id
+	`
+
+	queryCountSearch = `
+	SELECT COUNT(p.id) as total_data
+	FROM products p
+	JOIN categories c ON c.id = p.category_id
+	JOIN merchants m ON m.id = p.merchant_id
 	`
 )
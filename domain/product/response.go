@@ -28,10 +28,77 @@ func WriteError(c *fiber.Ctx, err error) error {
 		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40010", nil)
 	case err == entity.ErrImageUrlIsRequired:
 		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40007", nil)
-	case err == entity.ErrInvalidRole:
-		return write(c, http.StatusUnauthorized, "unauthorized", err.Error(), "40102", nil)
+	case err == entity.ErrInvalidQueryParam:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40011", nil)
+	case err == entity.ErrSortIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40012", nil)
+	case err == entity.ErrPriceRangeIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40013", nil)
+	case err == entity.ErrSearchQueryIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40014", nil)
+	case err == entity.ErrNoFieldsToUpdate:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40015", nil)
+	case err == entity.ErrOptionNameIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40016", nil)
+	case err == entity.ErrOptionValuesIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40017", nil)
+	case err == entity.ErrOptionIsDuplicate:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40018", nil)
+	case err == entity.ErrVariantsIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40019", nil)
+	case err == entity.ErrVariantOptionsIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40020", nil)
+	case err == entity.ErrVariantIsDuplicate:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40021", nil)
+	case err == entity.ErrVariantPriceIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40022", nil)
+	case err == entity.ErrVariantStockIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40023", nil)
+	case err == entity.ErrStockManagedByVariants:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40024", nil)
+	case err == entity.ErrImageUrlIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40025", nil)
+	case err == entity.ErrAltTextIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40026", nil)
+	case err == entity.ErrTooManyImages:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40027", nil)
+	case err == entity.ErrImageOrderIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40028", nil)
+	case err == entity.ErrImportFileIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40029", nil)
+	case err == entity.ErrImportFileIsTooLarge:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40030", nil)
+	case err == entity.ErrImportFileIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40031", nil)
+	case err == entity.ErrImportHeaderIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40032", nil)
+	case err == entity.ErrImportTooManyRows:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40033", nil)
+	case err == entity.ErrMovementTypeIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40034", nil)
+	case err == entity.ErrQuantityIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40035", nil)
+	case err == entity.ErrQuantityIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40036", nil)
+	case err == entity.ErrReasonIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40037", nil)
+	case err == entity.ErrReasonIsInvalid:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40038", nil)
+	case err == entity.ErrVariantIsRequired:
+		return write(c, http.StatusBadRequest, "bad request", err.Error(), "40039", nil)
+	case err == entity.ErrInsufficientStock:
+		return write(c, http.StatusConflict, "conflict", err.Error(), "40901", nil)
+	case err == entity.ErrVersionMismatch:
+		return write(c, http.StatusPreconditionFailed, "precondition failed", err.Error(), "41201", nil)
+	case err == entity.ErrMerchantNotFound:
+		return write(c, http.StatusForbidden, "forbidden", err.Error(), "40301", nil)
+	// another merchant's product is reported as not found so IDs cannot be probed
 	case err == entity.ErrProductNotFound:
 		return write(c, http.StatusNotFound, "not found", err.Error(), "40401", nil)
+	case err == entity.ErrProductImageNotFound:
+		return write(c, http.StatusNotFound, "not found", err.Error(), "40402", nil)
+	case err == entity.ErrVariantNotFound:
+		return write(c, http.StatusNotFound, "not found", err.Error(), "40403", nil)
 	default:
 		if iSSQLIntegrityConstraintViolation(err) {
 			return write(c, http.StatusInternalServerError, "internal server error", "error repository", "50001", nil)
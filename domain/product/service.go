@@ -3,6 +3,9 @@ package product
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
+	"io"
+	"strings"
 
 	"github.com/ecommerce/domain/category"
 	"github.com/ecommerce/domain/merchant"
@@ -10,12 +13,26 @@ import (
 	"github.com/ecommerce/entity"
 )
 
+const exportFlushRows = 100
+
 type Service interface {
 	CreateProduct(ctx context.Context, req entity.Product, token string) (err error)
 	GetListProduct(ctx context.Context, token, queryParam string, limit, page int) (response []dto.GetListProductResponse, totalData int, err error)
 	GetDetailProduct(ctx context.Context, id int, token string) (response dto.GetDetailProductResponse, err error)
 	UpdateProduct(ctx context.Context, req entity.Product, token string) (err error)
 	GetDetailProductUserPerspective(ctx context.Context, sku string) (response dto.GetDetailProductUserPerspectiveResponse, err error)
+	GetCatalog(ctx context.Context, filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error)
+	SearchProduct(ctx context.Context, filter entity.CatalogFilter) (response []dto.SearchProductResponse, totalData int, err error)
+	DeleteProduct(ctx context.Context, id int, token string) (err error)
+	RestoreProduct(ctx context.Context, id int, token string) (err error)
+	PatchProduct(ctx context.Context, id int, patch entity.ProductPatch, token string) (err error)
+	AttachProductImage(ctx context.Context, image entity.ProductImage, token string) (response dto.ProductImageResponse, err error)
+	ReorderProductImages(ctx context.Context, productId int, ids []int, token string) (response []dto.ProductImageResponse, err error)
+	RemoveProductImage(ctx context.Context, productId, imageId int, token string) (err error)
+	ImportProducts(ctx context.Context, file io.Reader, token string) (response dto.ImportProductResponse, err error)
+	ExportProducts(ctx context.Context, token string) (export func(ctx context.Context, w io.Writer) error, err error)
+	AdjustStock(ctx context.Context, movement entity.InventoryMovement, token string) (response dto.InventoryMovementResponse, err error)
+	GetStockMovements(ctx context.Context, productId int, token string, limit, page int) (response []dto.InventoryMovementResponse, totalData int, err error)
 }
 
 type ProductService struct {
@@ -33,16 +50,12 @@ func NewProductService(repository Repository, merchantRepository merchant.Reposi
 }
 
 func (p ProductService) CreateProduct(ctx context.Context, req entity.Product, token string) (err error) {
-	merchant, err := p.merchantRepository.GetByCreatedBy(ctx, token)
+	merchant, err := p.getMerchant(ctx, token)
 	if err != nil {
 		return
 	}
 	req.MerchantId = merchant.ID
 
-	if err = entity.NewProduct().CheckUserRole(merchant.Role); err != nil {
-		return
-	}
-
 	if _, err = p.categoryRepository.GetById(ctx, req.CategoryId); err != nil {
 		if sql.ErrNoRows == err {
 			err = entity.ErrCategoryNotFound
@@ -58,15 +71,11 @@ func (p ProductService) CreateProduct(ctx context.Context, req entity.Product, t
 }
 
 func (p ProductService) GetListProduct(ctx context.Context, token, queryParam string, limit, page int) (response []dto.GetListProductResponse, totalData int, err error) {
-	merchant, err := p.merchantRepository.GetByCreatedBy(ctx, token)
+	merchant, err := p.getMerchant(ctx, token)
 	if err != nil {
 		return
 	}
 
-	if err = entity.NewProduct().CheckUserRole(merchant.Role); err != nil {
-		return
-	}
-
 	products, totalData, err := p.repository.GetByMerchantId(ctx, queryParam, limit, page, merchant.ID)
 	if err != nil {
 		return
@@ -78,16 +87,12 @@ func (p ProductService) GetListProduct(ctx context.Context, token, queryParam st
 }
 
 func (p ProductService) GetDetailProduct(ctx context.Context, id int, token string) (response dto.GetDetailProductResponse, err error) {
-	merchant, err := p.merchantRepository.GetByCreatedBy(ctx, token)
+	merchant, err := p.getMerchant(ctx, token)
 	if err != nil {
 		return
 	}
 
-	if err = entity.NewProduct().CheckUserRole(merchant.Role); err != nil {
-		return
-	}
-
-	product, err := p.repository.GetById(ctx, id)
+	product, err := p.repository.GetById(ctx, id, merchant.ID)
 	if err != nil {
 		if sql.ErrNoRows == err {
 			err = entity.ErrProductNotFound
@@ -95,28 +100,76 @@ func (p ProductService) GetDetailProduct(ctx context.Context, id int, token stri
 		return
 	}
 
+	product.Images, err = p.repository.GetImages(ctx, product.ID)
+	if err != nil {
+		return
+	}
+
+	product.Options, product.Variants, err = p.repository.GetVariants(ctx, product.ID)
+	if err != nil {
+		return
+	}
+
 	response = entity.NewProduct().ProductDetailResponse(product)
 
 	return
 }
 
 func (p ProductService) UpdateProduct(ctx context.Context, req entity.Product, token string) (err error) {
-	merchant, err := p.merchantRepository.GetByCreatedBy(ctx, token)
+	merchant, err := p.getMerchant(ctx, token)
+	if err != nil {
+		return
+	}
+	req.MerchantId = merchant.ID
+
+	product, err := p.repository.GetById(ctx, req.ID, merchant.ID)
 	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrProductNotFound
+		}
+		return
+	}
+	req.Sku = product.Sku
+
+	if !req.ReplaceVariants && product.VariantCount > 0 {
+		req.Stock = product.Stock
+	}
+
+	if err = product.CheckVersion(req.Version, product.Version); err != nil {
 		return
 	}
+	req.Version = product.Version
 
-	if err = entity.NewProduct().CheckUserRole(merchant.Role); err != nil {
+	return p.update(ctx, req, token)
+}
+
+func (p ProductService) PatchProduct(ctx context.Context, id int, patch entity.ProductPatch, token string) (err error) {
+	merchant, err := p.getMerchant(ctx, token)
+	if err != nil {
 		return
 	}
 
-	if _, err = p.repository.GetById(ctx, req.ID); err != nil {
+	product, err := p.repository.GetById(ctx, id, merchant.ID)
+	if err != nil {
 		if sql.ErrNoRows == err {
 			err = entity.ErrProductNotFound
 		}
 		return
 	}
+	product.MerchantId = merchant.ID
+
+	if err = product.CheckVersion(patch.Version, product.Version); err != nil {
+		return
+	}
+
+	if patch.Stock != nil && product.VariantCount > 0 {
+		return entity.ErrStockManagedByVariants
+	}
+
+	return p.update(ctx, product.ApplyPatch(patch), token)
+}
 
+func (p ProductService) update(ctx context.Context, req entity.Product, token string) (err error) {
 	if _, err = p.categoryRepository.GetById(ctx, req.CategoryId); err != nil {
 		if sql.ErrNoRows == err {
 			err = entity.ErrCategoryNotFound
@@ -124,7 +177,11 @@ func (p ProductService) UpdateProduct(ctx context.Context, req entity.Product, t
 		return
 	}
 
+	req.UpdatedBy = &token
 	if err = p.repository.Update(ctx, req); err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrVersionMismatch
+		}
 		return
 	}
 
@@ -140,7 +197,323 @@ func (p ProductService) GetDetailProductUserPerspective(ctx context.Context, sku
 		return
 	}
 
+	product.Images, err = p.repository.GetImages(ctx, product.ID)
+	if err != nil {
+		return
+	}
+
+	product.Options, product.Variants, err = p.repository.GetVariants(ctx, product.ID)
+	if err != nil {
+		return
+	}
+
 	response = entity.NewProduct().ProductDetailUserPerspectiveResponse(product)
 
 	return
 }
+
+func (p ProductService) GetCatalog(ctx context.Context, filter entity.CatalogFilter) (response []dto.GetCatalogProductResponse, totalData int, err error) {
+	products, totalData, err := p.repository.GetCatalog(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewProduct().CatalogResponse(products)
+
+	return
+}
+
+func (p ProductService) SearchProduct(ctx context.Context, filter entity.CatalogFilter) (response []dto.SearchProductResponse, totalData int, err error) {
+	products, totalData, err := p.repository.Search(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewProduct().SearchResponse(products)
+
+	return
+}
+
+func (p ProductService) DeleteProduct(ctx context.Context, id int, token string) (err error) {
+	merchant, err := p.getMerchant(ctx, token)
+	if err != nil {
+		return
+	}
+
+	if err = p.repository.Delete(ctx, id, merchant.ID, token); err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrProductNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (p ProductService) RestoreProduct(ctx context.Context, id int, token string) (err error) {
+	merchant, err := p.getMerchant(ctx, token)
+	if err != nil {
+		return
+	}
+
+	if err = p.repository.Restore(ctx, id, merchant.ID, token); err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrProductNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (p ProductService) getMerchant(ctx context.Context, token string) (merchant entity.Merchant, err error) {
+	merchant, err = p.merchantRepository.GetByCreatedBy(ctx, token)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrMerchantNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (p ProductService) AttachProductImage(ctx context.Context, image entity.ProductImage, token string) (response dto.ProductImageResponse, err error) {
+	if _, err = p.getOwnedProduct(ctx, image.ProductId, token); err != nil {
+		return
+	}
+
+	images, err := p.repository.GetImages(ctx, image.ProductId)
+	if err != nil {
+		return
+	}
+
+	if len(images) >= entity.MaxProductImages {
+		err = entity.ErrTooManyImages
+		return
+	}
+
+	image, err = p.repository.AttachImage(ctx, image, token)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewProductImage().ImageResponse(image)
+
+	return
+}
+
+func (p ProductService) ReorderProductImages(ctx context.Context, productId int, ids []int, token string) (response []dto.ProductImageResponse, err error) {
+	if _, err = p.getOwnedProduct(ctx, productId, token); err != nil {
+		return
+	}
+
+	images, err := p.repository.GetImages(ctx, productId)
+	if err != nil {
+		return
+	}
+
+	if err = entity.NewProductImage().ValidateOrder(ids, images); err != nil {
+		return
+	}
+
+	if err = p.repository.ReorderImages(ctx, productId, ids, token); err != nil {
+		return
+	}
+
+	images, err = p.repository.GetImages(ctx, productId)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewProductImage().GalleryResponse(images)
+
+	return
+}
+
+func (p ProductService) RemoveProductImage(ctx context.Context, productId, imageId int, token string) (err error) {
+	if _, err = p.getOwnedProduct(ctx, productId, token); err != nil {
+		return
+	}
+
+	if err = p.repository.RemoveImage(ctx, productId, imageId, token); err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrProductImageNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (p ProductService) ImportProducts(ctx context.Context, file io.Reader, token string) (response dto.ImportProductResponse, err error) {
+	merchant, err := p.getMerchant(ctx, token)
+	if err != nil {
+		return
+	}
+
+	rows, err := entity.NewProduct().ParseImportCSV(file)
+	if err != nil {
+		return
+	}
+
+	categories, err := p.categoryRepository.GetAll(ctx)
+	if err != nil {
+		return
+	}
+
+	categoryIds := map[string]int{}
+	for _, category := range categories {
+		categoryIds[strings.ToLower(category.Name)] = category.ID
+	}
+
+	products := []entity.Product{}
+	response.Errors = []dto.ImportProductRowError{}
+
+	for _, row := range rows {
+		rowErr := row.Err
+
+		if rowErr == nil && row.Category != "" {
+			categoryId, ok := categoryIds[strings.ToLower(row.Category)]
+			if !ok {
+				rowErr = entity.ErrCategoryNotFound
+			}
+			row.Request.CategoryId = categoryId
+		}
+
+		var product entity.Product
+		if rowErr == nil {
+			product, rowErr = entity.NewProduct().Validate(row.Request, token)
+		}
+
+		if rowErr != nil {
+			response.Errors = append(response.Errors, dto.ImportProductRowError{
+				Line:  row.Line,
+				Error: rowErr.Error(),
+			})
+			continue
+		}
+
+		product.MerchantId = merchant.ID
+		products = append(products, product)
+	}
+
+	if len(products) > 0 {
+		if err = p.repository.CreateBatch(ctx, products); err != nil {
+			return dto.ImportProductResponse{}, err
+		}
+	}
+
+	response.TotalRows = len(rows)
+	response.Imported = len(products)
+	response.Failed = len(response.Errors)
+
+	return
+}
+
+func (p ProductService) ExportProducts(ctx context.Context, token string) (export func(ctx context.Context, w io.Writer) error, err error) {
+	merchant, err := p.getMerchant(ctx, token)
+	if err != nil {
+		return
+	}
+
+	export = func(ctx context.Context, w io.Writer) (err error) {
+		writer := csv.NewWriter(w)
+
+		if err = writer.Write(entity.ProductCSVHeader); err != nil {
+			return
+		}
+
+		rows := 0
+		err = p.repository.ExportByMerchantId(ctx, merchant.ID, func(product entity.Product) error {
+			if err := writer.Write(entity.NewProduct().CSVRecord(product)); err != nil {
+				return err
+			}
+
+			rows++
+			if rows%exportFlushRows != 0 {
+				return nil
+			}
+
+			writer.Flush()
+			return writer.Error()
+		})
+		if err != nil {
+			return
+		}
+
+		writer.Flush()
+		return writer.Error()
+	}
+
+	return
+}
+
+func (p ProductService) AdjustStock(ctx context.Context, movement entity.InventoryMovement, token string) (response dto.InventoryMovementResponse, err error) {
+	product, err := p.getOwnedProduct(ctx, movement.ProductId, token)
+	if err != nil {
+		return
+	}
+
+	if product.VariantCount > 0 && movement.VariantId == nil {
+		err = entity.ErrVariantIsRequired
+		return
+	}
+
+	if movement.VariantId != nil {
+		_, variants, err := p.repository.GetVariants(ctx, product.ID)
+		if err != nil {
+			return response, err
+		}
+
+		found := false
+		for _, variant := range variants {
+			found = found || variant.ID == *movement.VariantId
+		}
+
+		if !found {
+			return response, entity.ErrVariantNotFound
+		}
+	}
+
+	movement, err = p.repository.RecordMovement(ctx, movement)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewInventoryMovement().MovementResponse(movement)
+
+	return
+}
+
+func (p ProductService) GetStockMovements(ctx context.Context, productId int, token string, limit, page int) (response []dto.InventoryMovementResponse, totalData int, err error) {
+	if _, err = p.getOwnedProduct(ctx, productId, token); err != nil {
+		return
+	}
+
+	movements, totalData, err := p.repository.GetMovements(ctx, productId, limit, page)
+	if err != nil {
+		return
+	}
+
+	response = entity.NewInventoryMovement().MovementHistoryResponse(movements)
+
+	return
+}
+
+func (p ProductService) getOwnedProduct(ctx context.Context, id int, token string) (product entity.Product, err error) {
+	merchant, err := p.getMerchant(ctx, token)
+	if err != nil {
+		return
+	}
+
+	product, err = p.repository.GetById(ctx, id, merchant.ID)
+	if err != nil {
+		if sql.ErrNoRows == err {
+			err = entity.ErrProductNotFound
+		}
+		return
+	}
+
+	return
+}
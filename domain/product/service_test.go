@@ -1,8 +1,11 @@
 package product
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/ecommerce/dto"
@@ -21,9 +24,19 @@ func (mockProductRepository) Create(ctx context.Context, product entity.Product)
 	return CreateProduct()
 }
 
+// CreateBatch implements Repository.
+func (mockProductRepository) CreateBatch(ctx context.Context, products []entity.Product) (err error) {
+	return CreateProductBatch(products)
+}
+
+// ExportByMerchantId implements Repository.
+func (mockProductRepository) ExportByMerchantId(ctx context.Context, merchantId int, fn func(product entity.Product) error) (err error) {
+	return ExportProductByMerchantId(merchantId, fn)
+}
+
 // GetById implements Repository.
-func (mockProductRepository) GetById(ctx context.Context, id int) (product entity.Product, err error) {
-	return GetProductById()
+func (mockProductRepository) GetById(ctx context.Context, id int, merchantId int) (product entity.Product, err error) {
+	return GetProductById(id, merchantId)
 }
 
 // GetByMerchantId implements Repository.
@@ -38,7 +51,62 @@ func (mockProductRepository) GetBySku(ctx context.Context, sku string) (product
 
 // Update implements Repository.
 func (mockProductRepository) Update(ctx context.Context, product entity.Product) (err error) {
-	return UpdateProduct()
+	return UpdateProduct(product)
+}
+
+// GetCatalog implements Repository.
+func (mockProductRepository) GetCatalog(ctx context.Context, filter entity.CatalogFilter) (products []entity.Product, totalData int, err error) {
+	return nil, 0, nil
+}
+
+// Search implements Repository.
+func (mockProductRepository) Search(ctx context.Context, filter entity.CatalogFilter) (products []entity.Product, totalData int, err error) {
+	return SearchProduct()
+}
+
+// Delete implements Repository.
+func (mockProductRepository) Delete(ctx context.Context, id int, merchantId int, deletedBy string) (err error) {
+	return DeleteProduct(id, merchantId)
+}
+
+// Restore implements Repository.
+func (mockProductRepository) Restore(ctx context.Context, id int, merchantId int, updatedBy string) (err error) {
+	return RestoreProduct(id, merchantId)
+}
+
+// GetImages implements Repository.
+func (mockProductRepository) GetImages(ctx context.Context, productId int) (images []entity.ProductImage, err error) {
+	return GetProductImages(productId)
+}
+
+// AttachImage implements Repository.
+func (mockProductRepository) AttachImage(ctx context.Context, image entity.ProductImage, updatedBy string) (response entity.ProductImage, err error) {
+	return AttachProductImage(image)
+}
+
+// ReorderImages implements Repository.
+func (mockProductRepository) ReorderImages(ctx context.Context, productId int, ids []int, updatedBy string) (err error) {
+	return ReorderProductImages(productId, ids)
+}
+
+// RemoveImage implements Repository.
+func (mockProductRepository) RemoveImage(ctx context.Context, productId int, imageId int, updatedBy string) (err error) {
+	return RemoveProductImage(productId, imageId)
+}
+
+// RecordMovement implements Repository.
+func (mockProductRepository) RecordMovement(ctx context.Context, movement entity.InventoryMovement) (response entity.InventoryMovement, err error) {
+	return RecordProductMovement(movement)
+}
+
+// GetMovements implements Repository.
+func (mockProductRepository) GetMovements(ctx context.Context, productId int, limit int, page int) (movements []entity.InventoryMovement, totalData int, err error) {
+	return GetProductMovements(productId, limit, page)
+}
+
+// GetVariants implements Repository.
+func (mockProductRepository) GetVariants(ctx context.Context, productId int) (options []entity.ProductOption, variants []entity.ProductVariant, err error) {
+	return GetProductVariants(productId)
 }
 
 // GetByCreatedBy implements merchant.Repository.
@@ -46,6 +114,26 @@ func (mockMerchantRepository) GetByCreatedBy(ctx context.Context, createdBy stri
 	return GetMerchantByCreatedBy()
 }
 
+// GetById implements merchant.Repository.
+func (mockMerchantRepository) GetById(ctx context.Context, id int) (merchant entity.Merchant, err error) {
+	return entity.Merchant{}, nil
+}
+
+// Create implements merchant.Repository.
+func (mockMerchantRepository) Create(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error) {
+	return entity.Merchant{}, nil
+}
+
+// Update implements merchant.Repository.
+func (mockMerchantRepository) Update(ctx context.Context, merchant entity.Merchant) (response entity.Merchant, err error) {
+	return entity.Merchant{}, nil
+}
+
+// GetAlerts implements merchant.Repository.
+func (mockMerchantRepository) GetAlerts(ctx context.Context, merchantId, limit, page int) (notifications []entity.Notification, totalData int, err error) {
+	return nil, 0, nil
+}
+
 // Create implements category.Repository.
 func (mockCategoryRepository) Create(ctx context.Context, category entity.Category) (err error) {
 	return nil
@@ -53,7 +141,7 @@ func (mockCategoryRepository) Create(ctx context.Context, category entity.Catego
 
 // GetAll implements category.Repository.
 func (mockCategoryRepository) GetAll(ctx context.Context) (categories []entity.Category, err error) {
-	return nil, nil
+	return GetAllCategories()
 }
 
 // GetById implements category.Repository.
@@ -62,13 +150,26 @@ func (mockCategoryRepository) GetById(ctx context.Context, id int) (category ent
 }
 
 var (
-	CreateProduct          func() (err error)
-	GetProductById         func() (product entity.Product, err error)
-	GetProductByMerchantId func() (products []entity.Product, totalData int, err error)
-	GetProductBySku        func() (product entity.Product, err error)
-	UpdateProduct          func() (err error)
-	GetMerchantByCreatedBy func() (merchant entity.Merchant, err error)
-	GetCategoryById        func() (category entity.Category, err error)
+	CreateProduct             func() (err error)
+	CreateProductBatch        func(products []entity.Product) (err error)
+	ExportProductByMerchantId func(merchantId int, fn func(product entity.Product) error) (err error)
+	GetAllCategories          func() (categories []entity.Category, err error)
+	GetProductById            func(id, merchantId int) (product entity.Product, err error)
+	GetProductByMerchantId    func() (products []entity.Product, totalData int, err error)
+	GetProductBySku           func() (product entity.Product, err error)
+	UpdateProduct             func(product entity.Product) (err error)
+	GetMerchantByCreatedBy    func() (merchant entity.Merchant, err error)
+	GetCategoryById           func() (category entity.Category, err error)
+	SearchProduct             func() (products []entity.Product, totalData int, err error)
+	DeleteProduct             func(id, merchantId int) (err error)
+	RestoreProduct            func(id, merchantId int) (err error)
+	GetProductImages          func(productId int) (images []entity.ProductImage, err error)
+	AttachProductImage        func(image entity.ProductImage) (response entity.ProductImage, err error)
+	ReorderProductImages      func(productId int, ids []int) (err error)
+	RemoveProductImage        func(productId, imageId int) (err error)
+	RecordProductMovement     func(movement entity.InventoryMovement) (response entity.InventoryMovement, err error)
+	GetProductMovements       func(productId, limit, page int) (movements []entity.InventoryMovement, totalData int, err error)
+	GetProductVariants        func(productId int) (options []entity.ProductOption, variants []entity.ProductVariant, err error)
 )
 
 func init() {
@@ -151,40 +252,6 @@ func TestCreateProduct(t *testing.T) {
 				}
 			},
 		},
-		{
-			title:       "create product failed invalid role",
-			expectedErr: entity.ErrInvalidRole,
-			request: entity.Product{
-				ID:          1,
-				Name:        "product 1",
-				Description: "description",
-				Price:       10000,
-				Stock:       10,
-				CategoryId:  1,
-				ImageUrl:    "image.png",
-			},
-			before: func() {
-				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
-					return entity.Merchant{
-						ID:        1,
-						Name:      "merchant 1",
-						Role:      "user",
-						CreatedBy: "1",
-					}, nil
-				}
-
-				GetCategoryById = func() (category entity.Category, err error) {
-					return entity.Category{
-						ID:   1,
-						Name: "category 1",
-					}, nil
-				}
-
-				CreateProduct = func() (err error) {
-					return nil
-				}
-			},
-		},
 		{
 			title:       "create product failed category not found",
 			expectedErr: entity.ErrCategoryNotFound,
@@ -284,6 +351,17 @@ func TestGetDetailoProduct(t *testing.T) {
 				ImageUrl:    "image.png",
 				Sku:         "sku",
 				Category:    "category 1",
+				Images: []dto.ProductImageResponse{
+					{ID: 1, ImageUrl: "image.png", Position: 0},
+					{ID: 2, ImageUrl: "back.png", AltText: "back", Position: 1},
+				},
+				Options: []dto.ProductOptionResponse{
+					{Name: "size", Values: []string{"S", "M"}},
+				},
+				Variants: []dto.ProductVariantResponse{
+					{ID: 1, Sku: "sku-s", Options: map[string]string{"size": "S"}, Price: 10000, Stock: 4, ImageUrl: "image.png"},
+					{ID: 2, Sku: "sku-m", Options: map[string]string{"size": "M"}, Price: 12000, Stock: 6, ImageUrl: "image.png"},
+				},
 			},
 			before: func() {
 				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
@@ -295,7 +373,7 @@ func TestGetDetailoProduct(t *testing.T) {
 					}, nil
 				}
 
-				GetProductById = func() (product entity.Product, err error) {
+				GetProductById = func(id, merchantId int) (product entity.Product, err error) {
 					return entity.Product{
 						ID:           1,
 						Name:         "product 1",
@@ -311,6 +389,23 @@ func TestGetDetailoProduct(t *testing.T) {
 						MerchantCity: "city 1",
 					}, nil
 				}
+
+				GetProductImages = func(productId int) (images []entity.ProductImage, err error) {
+					return []entity.ProductImage{
+						{ID: 1, ProductId: productId, ImageUrl: "image.png", Position: 0},
+						{ID: 2, ProductId: productId, ImageUrl: "back.png", AltText: "back", Position: 1},
+					}, nil
+				}
+
+				GetProductVariants = func(productId int) (options []entity.ProductOption, variants []entity.ProductVariant, err error) {
+					price := 12000
+					return []entity.ProductOption{
+						{ID: 1, ProductId: productId, Name: "size", Values: entity.OptionValues{"S", "M"}},
+					}, []entity.ProductVariant{
+						{ID: 1, ProductId: productId, Sku: "sku-s", Options: entity.VariantOptions{"size": "S"}, Stock: 4},
+						{ID: 2, ProductId: productId, Sku: "sku-m", Options: entity.VariantOptions{"size": "M"}, Price: &price, Stock: 6},
+					}, nil
+				}
 			},
 		},
 		{
@@ -322,46 +417,54 @@ func TestGetDetailoProduct(t *testing.T) {
 					return entity.Merchant{}, errors.New("merchant not found")
 				}
 
-				GetProductById = func() (product entity.Product, err error) {
+				GetProductById = func(id, merchantId int) (product entity.Product, err error) {
 					return entity.Product{}, errors.New("merchant not found")
 				}
 			},
 		},
 		{
-			title:         "get product by id failed invalid role",
-			expectedErr:   entity.ErrInvalidRole,
+			title:         "get product by id failed product not found",
+			expectedErr:   entity.ErrProductNotFound,
 			expectedValue: dto.GetDetailProductResponse{},
 			before: func() {
 				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
 					return entity.Merchant{
 						ID:        1,
 						Name:      "merchant 1",
-						Role:      "user",
+						Role:      "merchant",
 						CreatedBy: "1",
 					}, nil
 				}
 
-				GetProductById = func() (product entity.Product, err error) {
-					return entity.Product{}, errors.New("merchant not found")
+				GetProductById = func(id, merchantId int) (product entity.Product, err error) {
+					return entity.Product{}, entity.ErrProductNotFound
 				}
 			},
 		},
 		{
-			title:         "get product by id failed product not found",
+			title:         "get product by id failed product owned by another merchant",
 			expectedErr:   entity.ErrProductNotFound,
 			expectedValue: dto.GetDetailProductResponse{},
 			before: func() {
 				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
 					return entity.Merchant{
-						ID:        1,
-						Name:      "merchant 1",
+						ID:        2,
+						Name:      "merchant 2",
 						Role:      "merchant",
 						CreatedBy: "1",
 					}, nil
 				}
 
-				GetProductById = func() (product entity.Product, err error) {
-					return entity.Product{}, entity.ErrProductNotFound
+				GetProductById = ownedProductById(1)
+			},
+		},
+		{
+			title:         "get product by id failed caller has no merchant",
+			expectedErr:   entity.ErrMerchantNotFound,
+			expectedValue: dto.GetDetailProductResponse{},
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, sql.ErrNoRows
 				}
 			},
 		},
@@ -379,7 +482,7 @@ func TestGetDetailoProduct(t *testing.T) {
 					}, nil
 				}
 
-				GetProductById = func() (product entity.Product, err error) {
+				GetProductById = func(id, merchantId int) (product entity.Product, err error) {
 					return entity.Product{}, errors.New("internal server error")
 				}
 			},
@@ -396,3 +499,814 @@ func TestGetDetailoProduct(t *testing.T) {
 		})
 	}
 }
+
+// ownedProductById mimics the merchant scoped query, a product is only found
+// when the caller's merchant owns it.
+func ownedProductById(owner int) func(id, merchantId int) (product entity.Product, err error) {
+	return func(id, merchantId int) (product entity.Product, err error) {
+		if merchantId != owner {
+			return entity.Product{}, sql.ErrNoRows
+		}
+
+		return entity.Product{
+			ID:          id,
+			Sku:         "existing-sku",
+			Name:        "product 1",
+			Description: "description",
+			Price:       10000,
+			Stock:       10,
+			CategoryId:  1,
+			ImageUrl:    "image.png",
+			MerchantId:  owner,
+			Version:     3,
+		}, nil
+	}
+}
+
+func TestUpdateProduct(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var updated entity.Product
+
+	var request = entity.Product{
+		ID:          1,
+		Name:        "product 1 updated",
+		Description: "description",
+		Price:       20000,
+		Stock:       5,
+		CategoryId:  1,
+		ImageUrl:    "image.png",
+		Sku:         "regenerated-by-validate",
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "update product success",
+			expectedErr: nil,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+				}
+
+				GetProductById = ownedProductById(1)
+
+				GetCategoryById = func() (category entity.Category, err error) {
+					return entity.Category{ID: 1}, nil
+				}
+
+				UpdateProduct = func(product entity.Product) (err error) {
+					updated = product
+					return nil
+				}
+			},
+		},
+		{
+			title:       "update product failed product owned by another merchant",
+			expectedErr: entity.ErrProductNotFound,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 2, CreatedBy: "2"}, nil
+				}
+
+				GetProductById = ownedProductById(1)
+
+				UpdateProduct = func(product entity.Product) (err error) {
+					updated = product
+					return nil
+				}
+			},
+		},
+		{
+			title:       "update product failed product changed before write",
+			expectedErr: entity.ErrVersionMismatch,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+				}
+
+				GetProductById = ownedProductById(1)
+
+				GetCategoryById = func() (category entity.Category, err error) {
+					return entity.Category{ID: 1}, nil
+				}
+
+				UpdateProduct = func(product entity.Product) (err error) {
+					return sql.ErrNoRows
+				}
+			},
+		},
+		{
+			title:       "update product failed caller has no merchant",
+			expectedErr: entity.ErrMerchantNotFound,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, sql.ErrNoRows
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			updated = entity.Product{}
+
+			test.before()
+
+			err := svc.UpdateProduct(context.Background(), request, "1")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 1, updated.MerchantId)
+				require.Equal(t, "product 1 updated", updated.Name)
+				require.Equal(t, "existing-sku", updated.Sku)
+				require.Equal(t, "1", *updated.UpdatedBy)
+				require.Equal(t, 3, updated.Version)
+			} else {
+				require.Equal(t, entity.Product{}, updated)
+			}
+		})
+	}
+}
+
+func TestUpdateProductVersion(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		version     int
+	}
+
+	var updated entity.Product
+
+	var testCases = []testCase{
+		{
+			title:       "update product success matching version",
+			expectedErr: nil,
+			version:     3,
+		},
+		{
+			title:       "update product success without precondition",
+			expectedErr: nil,
+			version:     0,
+		},
+		{
+			title:       "update product failed stale version",
+			expectedErr: entity.ErrVersionMismatch,
+			version:     2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			updated = entity.Product{}
+
+			GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+				return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+			}
+			GetProductById = ownedProductById(1)
+			GetCategoryById = func() (category entity.Category, err error) {
+				return entity.Category{ID: 1}, nil
+			}
+			UpdateProduct = func(product entity.Product) (err error) {
+				updated = product
+				return nil
+			}
+
+			err := svc.UpdateProduct(context.Background(), entity.Product{ID: 1, Name: "name", CategoryId: 1, Version: test.version}, "1")
+			require.Equal(t, test.expectedErr, err)
+
+			price := 1
+			err = svc.PatchProduct(context.Background(), 1, entity.ProductPatch{Version: test.version, Price: &price}, "1")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				// the write is always guarded by the version that was read
+				require.Equal(t, 3, updated.Version)
+			} else {
+				require.Equal(t, entity.Product{}, updated)
+			}
+		})
+	}
+}
+
+func TestProductVariantStock(t *testing.T) {
+	var updated entity.Product
+
+	GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+		return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+	}
+	GetProductById = func(id, merchantId int) (product entity.Product, err error) {
+		product, err = ownedProductById(1)(id, merchantId)
+		product.VariantCount = 2
+		return
+	}
+	GetCategoryById = func() (category entity.Category, err error) {
+		return entity.Category{ID: 1}, nil
+	}
+	UpdateProduct = func(product entity.Product) (err error) {
+		updated = product
+		return nil
+	}
+
+	t.Run("update without variants keeps the variant stock", func(t *testing.T) {
+		updated = entity.Product{}
+
+		err := svc.UpdateProduct(context.Background(), entity.Product{ID: 1, Name: "name", Stock: 99, CategoryId: 1}, "1")
+		require.Nil(t, err)
+		require.Equal(t, 10, updated.Stock)
+	})
+
+	t.Run("update with variants takes their stock", func(t *testing.T) {
+		updated = entity.Product{}
+
+		err := svc.UpdateProduct(context.Background(), entity.Product{ID: 1, Name: "name", Stock: 5, CategoryId: 1, ReplaceVariants: true}, "1")
+		require.Nil(t, err)
+		require.Equal(t, 5, updated.Stock)
+	})
+
+	t.Run("patch stock of a product with variants", func(t *testing.T) {
+		updated = entity.Product{}
+
+		stock := 1
+		err := svc.PatchProduct(context.Background(), 1, entity.ProductPatch{Stock: &stock}, "1")
+		require.Equal(t, entity.ErrStockManagedByVariants, err)
+		require.Equal(t, entity.Product{}, updated)
+	})
+}
+
+func TestSearchProduct(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "search product success",
+			expectedErr: nil,
+			before: func() {
+				SearchProduct = func() (products []entity.Product, totalData int, err error) {
+					return []entity.Product{
+						{
+							ID:            1,
+							Sku:           "test",
+							Name:          "Kopi Arabika",
+							MerchantId:    2,
+							MerchantName:  "Toko Maju",
+							MerchantCity:  "Jakarta",
+							Rank:          0.8,
+							NameHighlight: "\x02Kopi\x03 Arabika",
+							Snippet:       "biji \x02kopi\x03 pilihan",
+						},
+					}, 1, nil
+				}
+			},
+		},
+		{
+			title:       "search product failed: internal server error",
+			expectedErr: errors.New("internal server error"),
+			before: func() {
+				SearchProduct = func() (products []entity.Product, totalData int, err error) {
+					return nil, 0, errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			filter := entity.CatalogFilter{Query: "kopi", Sort: entity.SortRelevance, Limit: 10, Page: 1}
+			response, totalData, err := svc.SearchProduct(context.Background(), filter)
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 1, totalData)
+				require.Len(t, response, 1)
+				require.Equal(t, "<mark>Kopi</mark> Arabika", response[0].NameHighlight)
+				require.Equal(t, "biji <mark>kopi</mark> pilihan", response[0].Snippet)
+				require.Equal(t, 0.8, response[0].Rank)
+				require.Equal(t, "Toko Maju", response[0].Merchant.Name)
+			}
+		})
+	}
+}
+
+// ownedProductWrite mimics a merchant scoped UPDATE, no row matches when the
+// caller's merchant does not own the product.
+func ownedProductWrite(owner int) func(id, merchantId int) (err error) {
+	return func(id, merchantId int) (err error) {
+		if merchantId != owner {
+			return sql.ErrNoRows
+		}
+		return nil
+	}
+}
+
+func TestDeleteAndRestoreProduct(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		before      func()
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "delete and restore product success",
+			expectedErr: nil,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+				}
+				DeleteProduct = ownedProductWrite(1)
+				RestoreProduct = ownedProductWrite(1)
+			},
+		},
+		{
+			title:       "delete and restore product failed product owned by another merchant",
+			expectedErr: entity.ErrProductNotFound,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 2, CreatedBy: "2"}, nil
+				}
+				DeleteProduct = ownedProductWrite(1)
+				RestoreProduct = ownedProductWrite(1)
+			},
+		},
+		{
+			title:       "delete and restore product failed caller has no merchant",
+			expectedErr: entity.ErrMerchantNotFound,
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{}, sql.ErrNoRows
+				}
+			},
+		},
+		{
+			title:       "delete and restore product failed internal server error",
+			expectedErr: errors.New("internal server error"),
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+				}
+				DeleteProduct = func(id, merchantId int) (err error) {
+					return errors.New("internal server error")
+				}
+				RestoreProduct = func(id, merchantId int) (err error) {
+					return errors.New("internal server error")
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.before()
+
+			err := svc.DeleteProduct(context.Background(), 1, "1")
+			require.Equal(t, test.expectedErr, err)
+
+			err = svc.RestoreProduct(context.Background(), 1, "1")
+			require.Equal(t, test.expectedErr, err)
+		})
+	}
+}
+
+func TestPatchProduct(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		patch       entity.ProductPatch
+		before      func()
+	}
+
+	var updated entity.Product
+
+	price := 15000
+	categoryId := 9
+
+	var testCases = []testCase{
+		{
+			title:       "patch product success",
+			expectedErr: nil,
+			patch:       entity.ProductPatch{Price: &price},
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+				}
+
+				GetProductById = ownedProductById(1)
+
+				GetCategoryById = func() (category entity.Category, err error) {
+					return entity.Category{ID: 1}, nil
+				}
+
+				UpdateProduct = func(product entity.Product) (err error) {
+					updated = product
+					return nil
+				}
+			},
+		},
+		{
+			title:       "patch product failed product owned by another merchant",
+			expectedErr: entity.ErrProductNotFound,
+			patch:       entity.ProductPatch{Price: &price},
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 2, CreatedBy: "2"}, nil
+				}
+
+				GetProductById = ownedProductById(1)
+			},
+		},
+		{
+			title:       "patch product failed category not found",
+			expectedErr: entity.ErrCategoryNotFound,
+			patch:       entity.ProductPatch{CategoryId: &categoryId},
+			before: func() {
+				GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+					return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+				}
+
+				GetProductById = ownedProductById(1)
+
+				GetCategoryById = func() (category entity.Category, err error) {
+					return entity.Category{}, sql.ErrNoRows
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			updated = entity.Product{}
+
+			test.before()
+
+			err := svc.PatchProduct(context.Background(), 1, test.patch, "1")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, 15000, updated.Price)
+				require.Equal(t, "product 1", updated.Name)
+				require.Equal(t, "description", updated.Description)
+				require.Equal(t, 10, updated.Stock)
+				require.Equal(t, "existing-sku", updated.Sku)
+				require.Equal(t, 1, updated.MerchantId)
+				require.Equal(t, "1", *updated.UpdatedBy)
+			} else {
+				require.Equal(t, entity.Product{}, updated)
+			}
+		})
+	}
+}
+
+func TestProductImages(t *testing.T) {
+	gallery := func(n int) func(productId int) (images []entity.ProductImage, err error) {
+		return func(productId int) (images []entity.ProductImage, err error) {
+			images = []entity.ProductImage{}
+			for i := 1; i <= n; i++ {
+				images = append(images, entity.ProductImage{ID: i, ProductId: productId, Position: i - 1})
+			}
+			return images, nil
+		}
+	}
+
+	GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+		return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+	}
+	GetProductById = ownedProductById(1)
+
+	t.Run("attach image success", func(t *testing.T) {
+		GetProductImages = gallery(2)
+		AttachProductImage = func(image entity.ProductImage) (response entity.ProductImage, err error) {
+			image.ID = 3
+			image.Position = 2
+			return image, nil
+		}
+
+		response, err := svc.AttachProductImage(context.Background(), entity.ProductImage{ProductId: 1, ImageUrl: "https://cdn/image.png"}, "1")
+		require.Nil(t, err)
+		require.Equal(t, dto.ProductImageResponse{ID: 3, ImageUrl: "https://cdn/image.png", Position: 2}, response)
+	})
+
+	t.Run("attach image failed gallery full", func(t *testing.T) {
+		GetProductImages = gallery(entity.MaxProductImages)
+
+		_, err := svc.AttachProductImage(context.Background(), entity.ProductImage{ProductId: 1, ImageUrl: "https://cdn/image.png"}, "1")
+		require.Equal(t, entity.ErrTooManyImages, err)
+	})
+
+	t.Run("attach image failed product owned by another merchant", func(t *testing.T) {
+		GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+			return entity.Merchant{ID: 2, CreatedBy: "2"}, nil
+		}
+		defer func() {
+			GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+				return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+			}
+		}()
+
+		_, err := svc.AttachProductImage(context.Background(), entity.ProductImage{ProductId: 1, ImageUrl: "https://cdn/image.png"}, "2")
+		require.Equal(t, entity.ErrProductNotFound, err)
+	})
+
+	t.Run("reorder images success", func(t *testing.T) {
+		var reordered []int
+		GetProductImages = gallery(3)
+		ReorderProductImages = func(productId int, ids []int) (err error) {
+			reordered = ids
+			return nil
+		}
+
+		_, err := svc.ReorderProductImages(context.Background(), 1, []int{3, 1, 2}, "1")
+		require.Nil(t, err)
+		require.Equal(t, []int{3, 1, 2}, reordered)
+	})
+
+	t.Run("reorder images failed incomplete order", func(t *testing.T) {
+		GetProductImages = gallery(3)
+
+		_, err := svc.ReorderProductImages(context.Background(), 1, []int{3, 1, 1}, "1")
+		require.Equal(t, entity.ErrImageOrderIsInvalid, err)
+	})
+
+	t.Run("remove image failed image not found", func(t *testing.T) {
+		RemoveProductImage = func(productId, imageId int) (err error) {
+			return sql.ErrNoRows
+		}
+
+		err := svc.RemoveProductImage(context.Background(), 1, 9, "1")
+		require.Equal(t, entity.ErrProductImageNotFound, err)
+	})
+}
+
+func TestImportProducts(t *testing.T) {
+	var imported []entity.Product
+
+	GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+		return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+	}
+	GetAllCategories = func() (categories []entity.Category, err error) {
+		return []entity.Category{{ID: 1, Name: "Fashion"}, {ID: 2, Name: "Books"}}, nil
+	}
+	CreateProductBatch = func(products []entity.Product) (err error) {
+		imported = products
+		return nil
+	}
+
+	t.Run("import valid rows and report the invalid ones", func(t *testing.T) {
+		imported = nil
+
+		file := strings.NewReader(strings.Join([]string{
+			"name,description,price,stock,category,image_url",
+			"shirt,cotton shirt,10000,5,fashion,shirt.png",
+			"novel,,20000,3,Books,novel.png",
+			"mug,ceramic mug,5000,2,Kitchen,mug.png",
+			"book,hardcover,abc,1,Books,book.png",
+			"cap,wool cap,7000,1,Fashion,cap.png",
+		}, "\n"))
+
+		response, err := svc.ImportProducts(context.Background(), file, "1")
+		require.Nil(t, err)
+		require.Equal(t, dto.ImportProductResponse{
+			TotalRows: 5,
+			Imported:  2,
+			Failed:    3,
+			Errors: []dto.ImportProductRowError{
+				{Line: 3, Error: entity.ErrDescriptionIsRequired.Error()},
+				{Line: 4, Error: entity.ErrCategoryNotFound.Error()},
+				{Line: 5, Error: entity.ErrPriceIsInvalid.Error()},
+			},
+		}, response)
+
+		require.Len(t, imported, 2)
+		require.Equal(t, "shirt", imported[0].Name)
+		require.Equal(t, 1, imported[0].CategoryId)
+		require.Equal(t, 1, imported[0].MerchantId)
+		require.NotEmpty(t, imported[0].Sku)
+		require.Equal(t, "cap", imported[1].Name)
+	})
+
+	t.Run("import nothing when every row is invalid", func(t *testing.T) {
+		imported = nil
+
+		file := strings.NewReader("name,description,price,stock,category,image_url\nmug,ceramic mug,5000,2,Kitchen,mug.png\n")
+
+		response, err := svc.ImportProducts(context.Background(), file, "1")
+		require.Nil(t, err)
+		require.Equal(t, 0, response.Imported)
+		require.Equal(t, 1, response.Failed)
+		require.Nil(t, imported)
+	})
+
+	t.Run("import failed invalid header", func(t *testing.T) {
+		_, err := svc.ImportProducts(context.Background(), strings.NewReader("name,price\nshirt,1000\n"), "1")
+		require.Equal(t, entity.ErrImportHeaderIsInvalid, err)
+	})
+
+	t.Run("import failed batch insert", func(t *testing.T) {
+		CreateProductBatch = func(products []entity.Product) (err error) {
+			return errors.New("internal server error")
+		}
+
+		file := strings.NewReader("name,description,price,stock,category,image_url\nshirt,cotton shirt,10000,5,fashion,shirt.png\n")
+
+		response, err := svc.ImportProducts(context.Background(), file, "1")
+		require.Equal(t, errors.New("internal server error"), err)
+		require.Equal(t, dto.ImportProductResponse{}, response)
+	})
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write(b []byte) (int, error) {
+	return 0, errWriteFailed
+}
+
+func TestExportProducts(t *testing.T) {
+	t.Run("export writes the merchant catalog", func(t *testing.T) {
+		var exportedMerchantId int
+
+		GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+			return entity.Merchant{ID: 4, CreatedBy: "1"}, nil
+		}
+		ExportProductByMerchantId = func(merchantId int, fn func(product entity.Product) error) (err error) {
+			exportedMerchantId = merchantId
+			return fn(entity.Product{Sku: "sku", Name: "=cmd", Description: "a, b", Price: 1000, Stock: 2, Category: "Books", ImageUrl: "book.png"})
+		}
+
+		export, err := svc.ExportProducts(context.Background(), "1")
+		require.Nil(t, err)
+
+		var buffer bytes.Buffer
+		require.Nil(t, export(context.Background(), &buffer))
+		require.Equal(t, 4, exportedMerchantId)
+		require.Equal(t, "sku,name,description,price,stock,category,image_url\nsku,'=cmd,\"a, b\",1000,2,Books,book.png\n", buffer.String())
+	})
+
+	t.Run("export stops when the writer fails", func(t *testing.T) {
+		var written int
+
+		GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+			return entity.Merchant{ID: 4, CreatedBy: "1"}, nil
+		}
+		ExportProductByMerchantId = func(merchantId int, fn func(product entity.Product) error) (err error) {
+			for i := 0; i < 10*exportFlushRows; i++ {
+				if err = fn(entity.Product{Sku: "sku", Name: "name"}); err != nil {
+					return
+				}
+				written++
+			}
+			return
+		}
+
+		export, err := svc.ExportProducts(context.Background(), "1")
+		require.Nil(t, err)
+
+		require.Equal(t, errWriteFailed, export(context.Background(), failingWriter{}))
+		require.Equal(t, exportFlushRows-1, written)
+	})
+
+	t.Run("export failed caller has no merchant", func(t *testing.T) {
+		GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+			return entity.Merchant{}, sql.ErrNoRows
+		}
+
+		export, err := svc.ExportProducts(context.Background(), "1")
+		require.Equal(t, entity.ErrMerchantNotFound, err)
+		require.Nil(t, export)
+	})
+}
+
+func TestAdjustStock(t *testing.T) {
+	type testCase struct {
+		title       string
+		expectedErr error
+		movement    entity.InventoryMovement
+		before      func()
+	}
+
+	variantId := 2
+	otherVariantId := 9
+
+	var recorded entity.InventoryMovement
+
+	withVariants := func(id, merchantId int) (product entity.Product, err error) {
+		product, err = ownedProductById(1)(id, merchantId)
+		product.VariantCount = 1
+		return
+	}
+
+	var testCases = []testCase{
+		{
+			title:       "adjust stock success",
+			expectedErr: nil,
+			movement:    entity.InventoryMovement{ProductId: 1, Type: entity.MovementRestock, Quantity: 5, CreatedBy: "1"},
+			before: func() {
+				GetProductById = ownedProductById(1)
+			},
+		},
+		{
+			title:       "adjust stock failed product owned by another merchant",
+			expectedErr: entity.ErrProductNotFound,
+			movement:    entity.InventoryMovement{ProductId: 1, Type: entity.MovementRestock, Quantity: 5, CreatedBy: "1"},
+			before: func() {
+				GetProductById = ownedProductById(2)
+			},
+		},
+		{
+			title:       "adjust stock failed variant is required",
+			expectedErr: entity.ErrVariantIsRequired,
+			movement:    entity.InventoryMovement{ProductId: 1, Type: entity.MovementRestock, Quantity: 5, CreatedBy: "1"},
+			before: func() {
+				GetProductById = withVariants
+			},
+		},
+		{
+			title:       "adjust stock success for a variant",
+			expectedErr: nil,
+			movement:    entity.InventoryMovement{ProductId: 1, VariantId: &variantId, Type: entity.MovementRestock, Quantity: 5, CreatedBy: "1"},
+			before: func() {
+				GetProductById = withVariants
+			},
+		},
+		{
+			title:       "adjust stock failed variant of another product",
+			expectedErr: entity.ErrVariantNotFound,
+			movement:    entity.InventoryMovement{ProductId: 1, VariantId: &otherVariantId, Type: entity.MovementRestock, Quantity: 5, CreatedBy: "1"},
+			before: func() {
+				GetProductById = withVariants
+			},
+		},
+		{
+			title:       "adjust stock failed insufficient stock",
+			expectedErr: entity.ErrInsufficientStock,
+			movement:    entity.InventoryMovement{ProductId: 1, Type: entity.MovementAdjustment, Quantity: -50, Reason: "damaged", CreatedBy: "1"},
+			before: func() {
+				GetProductById = ownedProductById(1)
+				RecordProductMovement = func(movement entity.InventoryMovement) (response entity.InventoryMovement, err error) {
+					return entity.InventoryMovement{}, entity.ErrInsufficientStock
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			recorded = entity.InventoryMovement{}
+
+			GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+				return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+			}
+			GetProductVariants = func(productId int) (options []entity.ProductOption, variants []entity.ProductVariant, err error) {
+				return nil, []entity.ProductVariant{{ID: 1, ProductId: productId}, {ID: 2, ProductId: productId}}, nil
+			}
+			RecordProductMovement = func(movement entity.InventoryMovement) (response entity.InventoryMovement, err error) {
+				recorded = movement
+				movement.ID = 1
+				movement.StockAfter = 15
+				return movement, nil
+			}
+
+			test.before()
+
+			response, err := svc.AdjustStock(context.Background(), test.movement, "1")
+			require.Equal(t, test.expectedErr, err)
+
+			if test.expectedErr == nil {
+				require.Equal(t, test.movement, recorded)
+				require.Equal(t, 15, response.StockAfter)
+			} else {
+				require.Equal(t, entity.InventoryMovement{}, recorded)
+			}
+		})
+	}
+}
+
+func TestGetStockMovements(t *testing.T) {
+	GetMerchantByCreatedBy = func() (merchant entity.Merchant, err error) {
+		return entity.Merchant{ID: 1, CreatedBy: "1"}, nil
+	}
+	GetProductById = ownedProductById(1)
+	GetProductMovements = func(productId, limit, page int) (movements []entity.InventoryMovement, totalData int, err error) {
+		return []entity.InventoryMovement{
+			{ID: 2, ProductId: productId, Type: entity.MovementSale, Quantity: -1, StockAfter: 9},
+			{ID: 1, ProductId: productId, Type: entity.MovementRestock, Quantity: 10, StockAfter: 10},
+		}, 2, nil
+	}
+
+	response, totalData, err := svc.GetStockMovements(context.Background(), 1, "1", 10, 1)
+	require.Nil(t, err)
+	require.Equal(t, 2, totalData)
+	require.Len(t, response, 2)
+	require.Equal(t, entity.MovementSale, response[0].Type)
+	require.Equal(t, 9, response[0].StockAfter)
+}
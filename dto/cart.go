@@ -0,0 +1,42 @@
+package dto
+
+type AddCartItemRequest struct {
+	Sku      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity"`
+}
+
+type GetCartResponse struct {
+	Merchants     []CartMerchantResponse `json:"merchants"`
+	TotalQuantity int                    `json:"total_quantity"`
+	TotalPrice    int                    `json:"total_price"`
+	HasChanges    bool                   `json:"has_changes"`
+}
+
+type CartMerchantResponse struct {
+	MerchantId   int                `json:"merchant_id"`
+	MerchantName string             `json:"merchant_name"`
+	MerchantCity string             `json:"merchant_city"`
+	Items        []CartItemResponse `json:"items"`
+	Subtotal     int                `json:"subtotal"`
+}
+
+type CartItemResponse struct {
+	Sku           string `json:"sku"`
+	ProductId     int    `json:"product_id"`
+	VariantId     *int   `json:"variant_id,omitempty"`
+	Name          string `json:"name"`
+	ImageUrl      string `json:"image_url"`
+	Quantity      int    `json:"quantity"`
+	Price         int    `json:"price"`
+	PreviousPrice *int   `json:"previous_price,omitempty"`
+	Stock         int    `json:"stock"`
+	Subtotal      int    `json:"subtotal"`
+	PriceChanged  bool   `json:"price_changed"`
+	OutOfStock    bool   `json:"out_of_stock"`
+	Unavailable   bool   `json:"unavailable"`
+	HasVariants   bool   `json:"has_variants"`
+}
@@ -5,3 +5,40 @@ type Merchant struct {
 	Name string `json:"name"`
 	City string `json:"city"`
 }
+
+type GetMerchantStorefrontResponse struct {
+	Merchant
+	Address  string                   `json:"address"`
+	ImageUrl string                   `json:"image_url"`
+	Products []GetListProductResponse `json:"products"`
+}
+
+type CreateOrUpdateMerchantRequest struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Address     string `json:"address"`
+	City        string `json:"city"`
+	ImageUrl    string `json:"image_url"`
+}
+
+type GetMerchantProfileResponse struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Address     string `json:"address"`
+	City        string `json:"city"`
+	ImageUrl    string `json:"image_url"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type GetMerchantAlertResponse struct {
+	ID        int    `json:"id"`
+	ProductId int    `json:"product_id"`
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	Stock     int    `json:"stock"`
+	Threshold int    `json:"threshold"`
+	CreatedAt string `json:"created_at"`
+}
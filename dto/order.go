@@ -1 +1,51 @@
 package dto
+
+type CheckoutRequest struct {
+	Items []CheckoutItemRequest `json:"items"`
+}
+
+type CheckoutItemRequest struct {
+	Sku      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type CheckoutResponse struct {
+	Orders     []GetOrderResponse `json:"orders"`
+	TotalPrice int                `json:"total_price"`
+}
+
+type GetOrderResponse struct {
+	ID               string                   `json:"id"`
+	MerchantId       int                      `json:"merchant_id"`
+	TrxId            string                   `json:"trx_id"`
+	Status           string                   `json:"status"`
+	TotalPrice       int                      `json:"total_price"`
+	InvoiceUrl       string                   `json:"invoice_url"`
+	PaymentExpiresAt *string                  `json:"payment_expires_at,omitempty"`
+	PaidAfterCloseAt *string                  `json:"paid_after_close_at,omitempty"`
+	Items            []GetOrderDetailResponse `json:"items"`
+	CreatedAt        string                   `json:"created_at"`
+}
+
+type GetOrderDetailResponse struct {
+	ProductId         int    `json:"product_id"`
+	VariantId         *int   `json:"variant_id,omitempty"`
+	Sku               string `json:"sku"`
+	Name              string `json:"name"`
+	Price             int    `json:"price"`
+	Quantity          int    `json:"quantity"`
+	TotalPriceProduct int    `json:"total_price_product"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type OrderStatusHistoryResponse struct {
+	FromStatus *string `json:"from_status"`
+	ToStatus   string  `json:"to_status"`
+	Actor      string  `json:"actor"`
+	Reason     string  `json:"reason"`
+	CreatedAt  string  `json:"created_at"`
+}
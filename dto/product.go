@@ -8,15 +8,62 @@ type CreateOrUpdateProductRequest struct {
 	Stock       int    `json:"stock"`
 	CategoryId  int    `json:"category_id"`
 	ImageUrl    string `json:"image_url"`
+
+	LowStockThreshold int                     `json:"low_stock_threshold"`
+	Options           []ProductOptionRequest  `json:"options"`
+	Variants          []ProductVariantRequest `json:"variants"`
+}
+
+type ProductOptionRequest struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type ProductVariantRequest struct {
+	Options  map[string]string `json:"options"`
+	Price    *int              `json:"price"`
+	Stock    int               `json:"stock"`
+	ImageUrl string            `json:"image_url"`
+}
+
+type AttachProductImageRequest struct {
+	ImageUrl string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+type ReorderProductImagesRequest struct {
+	ImageIds []int `json:"image_ids"`
+}
+
+type ProductImageResponse struct {
+	ID       int    `json:"id"`
+	ImageUrl string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+	Position int    `json:"position"`
+}
+
+type ProductOptionResponse struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type ProductVariantResponse struct {
+	ID       int               `json:"id"`
+	Sku      string            `json:"sku"`
+	Options  map[string]string `json:"options"`
+	Price    int               `json:"price"`
+	Stock    int               `json:"stock"`
+	ImageUrl string            `json:"image_url"`
 }
 
 type UpdateProductRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Price       int    `json:"price"`
-	Stock       int    `json:"stock"`
-	CategoryId  int    `json:"category_id"`
-	ImageUrl    string `json:"image_url"`
+	Name              *string `json:"name"`
+	Description       *string `json:"description"`
+	Price             *int    `json:"price"`
+	Stock             *int    `json:"stock"`
+	CategoryId        *int    `json:"category_id"`
+	ImageUrl          *string `json:"image_url"`
+	LowStockThreshold *int    `json:"low_stock_threshold"`
 }
 
 type GetListProductResponse struct {
@@ -30,40 +77,89 @@ type GetListProductResponse struct {
 	ImageUrl    string `json:"image_url"`
 }
 
+type GetCatalogRequest struct {
+	Query      string `query:"query"`
+	CategoryId int    `query:"category_id"`
+	MinPrice   int    `query:"min_price"`
+	MaxPrice   int    `query:"max_price"`
+	City       string `query:"city"`
+	InStock    bool   `query:"in_stock"`
+	Sort       string `query:"sort"`
+	Limit      int    `query:"limit"`
+	Page       int    `query:"page"`
+}
+
+type GetCatalogProductResponse struct {
+	ID         int      `json:"id"`
+	Sku        string   `json:"sku"`
+	Name       string   `json:"name"`
+	Price      int      `json:"price"`
+	Stock      int      `json:"stock"`
+	Category   string   `json:"category"`
+	CategoryId int      `json:"category_id"`
+	Merchant   Merchant `json:"merchant"`
+	ImageUrl   string   `json:"image_url"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type SearchProductResponse struct {
+	ID            int      `json:"id"`
+	Sku           string   `json:"sku"`
+	Name          string   `json:"name"`
+	Price         int      `json:"price"`
+	Stock         int      `json:"stock"`
+	Category      string   `json:"category"`
+	CategoryId    int      `json:"category_id"`
+	Merchant      Merchant `json:"merchant"`
+	ImageUrl      string   `json:"image_url"`
+	NameHighlight string   `json:"name_highlight"`
+	Snippet       string   `json:"snippet"`
+	Rank          float64  `json:"rank"`
+}
+
 type PaginationResponse struct {
 	Query     string `json:"query"`
 	Limit     int    `json:"limit"`
 	Page      int    `json:"page"`
 	TotalPage int    `json:"total_page"`
+	TotalData int    `json:"total_data"`
 }
 
 type GetDetailProductResponse struct {
-	ID          int    `json:"id"`
-	Sku         string `json:"sku"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Price       int    `json:"price"`
-	Stock       int    `json:"stock"`
-	Category    string `json:"category"`
-	CategoryId  int    `json:"category_id"`
-	ImageUrl    string `json:"image_url"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID                int                      `json:"id"`
+	Sku               string                   `json:"sku"`
+	Name              string                   `json:"name"`
+	Description       string                   `json:"description"`
+	Price             int                      `json:"price"`
+	Stock             int                      `json:"stock"`
+	Category          string                   `json:"category"`
+	CategoryId        int                      `json:"category_id"`
+	ImageUrl          string                   `json:"image_url"`
+	LowStockThreshold int                      `json:"low_stock_threshold"`
+	Images            []ProductImageResponse   `json:"images"`
+	Options           []ProductOptionResponse  `json:"options"`
+	Variants          []ProductVariantResponse `json:"variants"`
+	Version           int                      `json:"version"`
+	CreatedAt         string                   `json:"created_at"`
+	UpdatedAt         string                   `json:"updated_at"`
 }
 
 type GetDetailProductUserPerspectiveResponse struct {
-	ID          int      `json:"id"`
-	Sku         string   `json:"sku"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Price       int      `json:"price"`
-	Stock       int      `json:"stock"`
-	Category    string   `json:"category"`
-	CategoryId  int      `json:"category_id"`
-	Merchant    Merchant `json:"merchant"`
-	ImageUrl    string   `json:"image_url"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
+	ID          int                      `json:"id"`
+	Sku         string                   `json:"sku"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Price       int                      `json:"price"`
+	Stock       int                      `json:"stock"`
+	Category    string                   `json:"category"`
+	CategoryId  int                      `json:"category_id"`
+	Merchant    Merchant                 `json:"merchant"`
+	ImageUrl    string                   `json:"image_url"`
+	Images      []ProductImageResponse   `json:"images"`
+	Options     []ProductOptionResponse  `json:"options"`
+	Variants    []ProductVariantResponse `json:"variants"`
+	CreatedAt   string                   `json:"created_at"`
+	UpdatedAt   string                   `json:"updated_at"`
 }
 
 func CountTotalPage(total, limit int) int {
@@ -79,5 +175,37 @@ func NewPaginationResponse(queryParam string, limit, page, totalData int) Pagina
 		Limit:     limit,
 		Page:      page,
 		TotalPage: CountTotalPage(totalData, limit),
+		TotalData: totalData,
 	}
 }
+
+type ImportProductResponse struct {
+	TotalRows int                     `json:"total_rows"`
+	Imported  int                     `json:"imported"`
+	Failed    int                     `json:"failed"`
+	Errors    []ImportProductRowError `json:"errors"`
+}
+
+type ImportProductRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type CreateStockAdjustmentRequest struct {
+	Type      string `json:"type"`
+	VariantId *int   `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+	Reason    string `json:"reason"`
+}
+
+type InventoryMovementResponse struct {
+	ID         int    `json:"id"`
+	ProductId  int    `json:"product_id"`
+	VariantId  *int   `json:"variant_id"`
+	Type       string `json:"type"`
+	Quantity   int    `json:"quantity"`
+	StockAfter int    `json:"stock_after"`
+	Reason     string `json:"reason"`
+	CreatedBy  string `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+}
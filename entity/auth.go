@@ -1,11 +1,16 @@
 package entity
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"regexp"
+	"time"
 
 	"github.com/ecommerce/dto"
 	"github.com/ecommerce/infra/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,15 +22,42 @@ var (
 	ErrEmailAlreadyUsed       = errors.New("email already used")
 	ErrInvalidEmailOrPassword = errors.New("invalid email or password")
 	ErrUserAlreadyMerchant    = errors.New("user already as a merchant")
+	ErrRefreshTokenIsRequired = errors.New("refresh_token is required")
+	ErrInvalidRefreshToken    = errors.New("invalid or expired refresh token")
+	ErrResetTokenIsRequired   = errors.New("reset token is required")
+	ErrInvalidResetToken      = errors.New("invalid or expired reset token")
+	ErrVerifyTokenIsRequired  = errors.New("verification token is required")
+	ErrInvalidVerifyToken     = errors.New("invalid or expired verification token")
+	ErrEmailNotVerified       = errors.New("email is not verified")
+
+	ErrVerificationPolicyIsInvalid = errors.New("verification policy must be login or merchant")
+)
+
+const (
+	RoleUser     = "user"
+	RoleMerchant = "merchant"
+	RoleAdmin    = "admin"
+)
+
+const (
+	VerificationPolicyLogin    = "login"
+	VerificationPolicyMerchant = "merchant"
 )
 
 var EmailPattern string = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 
 type Auth struct {
-	ID       string `db:"id"`
-	Email    string `db:"email"`
-	Password string `db:"password"`
-	Role     string `db:"role"`
+	ID              string  `db:"id"`
+	Email           string  `db:"email"`
+	Password        string  `db:"password"`
+	Role            string  `db:"role"`
+	EmailVerifiedAt *string `db:"email_verified_at"`
+}
+
+type Session struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	SessionID string `json:"session_id"`
 }
 
 func NewAuth() Auth {
@@ -33,29 +65,87 @@ func NewAuth() Auth {
 }
 
 func (a Auth) Validate(req dto.AuthRequest) (Auth, error) {
-	if req.Email == "" {
-		return a, ErrEmailIsRequired
+	if err := validateEmail(req.Email); err != nil {
+		return a, err
 	}
 
-	emailRegex := regexp.MustCompile(EmailPattern)
-	if !emailRegex.MatchString(req.Email) {
-		return a, ErrEmailIsInvalid
+	if err := validatePassword(req.Password); err != nil {
+		return a, err
 	}
 
-	if req.Password == "" {
-		return a, ErrPasswordIsEmpty
+	a.Email = req.Email
+	a.Password = req.Password
+
+	return a, nil
+}
+
+func (a Auth) ValidateForgotPassword(req dto.ForgotPasswordRequest) (Auth, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return a, err
 	}
 
-	if len(req.Password) < 6 {
-		return a, ErrPasswordLength
+	a.Email = req.Email
+
+	return a, nil
+}
+
+func (a Auth) ValidateResendVerification(req dto.ResendVerificationRequest) (Auth, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return a, err
 	}
 
 	a.Email = req.Email
+
+	return a, nil
+}
+
+func (a Auth) ValidateVerificationPolicy(policy string) (err error) {
+	if policy != VerificationPolicyLogin && policy != VerificationPolicyMerchant {
+		return ErrVerificationPolicyIsInvalid
+	}
+
+	return
+}
+
+func (a Auth) ValidateResetPassword(req dto.ResetPasswordRequest) (Auth, error) {
+	if req.Token == "" {
+		return a, ErrResetTokenIsRequired
+	}
+
+	if err := validatePassword(req.Password); err != nil {
+		return a, err
+	}
+
 	a.Password = req.Password
 
 	return a, nil
 }
 
+func validateEmail(email string) error {
+	if email == "" {
+		return ErrEmailIsRequired
+	}
+
+	emailRegex := regexp.MustCompile(EmailPattern)
+	if !emailRegex.MatchString(email) {
+		return ErrEmailIsInvalid
+	}
+
+	return nil
+}
+
+func validatePassword(password string) error {
+	if password == "" {
+		return ErrPasswordIsEmpty
+	}
+
+	if len(password) < 6 {
+		return ErrPasswordLength
+	}
+
+	return nil
+}
+
 func (a Auth) CheckRequestEmail(reqEmail, existsEmail string) (err error) {
 	if reqEmail == existsEmail {
 		return ErrEmailAlreadyUsed
@@ -87,11 +177,15 @@ func (a Auth) ValidatePasswordFromPlainText(password, hash string) bool {
 	return err == nil
 }
 
-func (a Auth) GenerateAccessToken(accessToken string) (token string, err error) {
+func (a Auth) GenerateAccessToken(sessionID string, lifeTimeHour int) (token string, err error) {
 	token, err = middleware.GenerateNewJWT(&middleware.Claims{
-		ID:    a.ID,
-		Email: a.Email,
-		Role:  a.Role,
+		ID:        a.ID,
+		Email:     a.Email,
+		Role:      a.Role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(lifeTimeHour) * time.Hour)),
+		},
 	})
 	if err != nil {
 		return "", err
@@ -100,8 +194,49 @@ func (a Auth) GenerateAccessToken(accessToken string) (token string, err error)
 	return token, nil
 }
 
+func (a Auth) GenerateRandomToken() (token string, err error) {
+	buffer := make([]byte, 32)
+	if _, err = rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
+
+func (a Auth) NewSession() Session {
+	return Session{
+		ID:        a.ID,
+		Email:     a.Email,
+		SessionID: uuid.New().String(),
+	}
+}
+
+func (a Auth) ValidateRefreshToken(req dto.RefreshTokenRequest) (err error) {
+	if req.RefreshToken == "" {
+		return ErrRefreshTokenIsRequired
+	}
+
+	return
+}
+
+func (a Auth) ValidateVerifyToken(token string) (err error) {
+	if token == "" {
+		return ErrVerifyTokenIsRequired
+	}
+
+	return
+}
+
+func (a Auth) CheckEmailVerified() (err error) {
+	if a.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+
+	return
+}
+
 func (a Auth) ValidateUserRole(role string) (err error) {
-	if role == "merchant" {
+	if role == RoleMerchant {
 		return ErrUserAlreadyMerchant
 	}
 
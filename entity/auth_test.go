@@ -135,10 +135,80 @@ func TestEntityAuth(t *testing.T) {
 	})
 
 	t.Run("success : generate access token", func(t *testing.T) {
-		token, err := NewAuth().GenerateAccessToken("token")
+		token, err := NewAuth().GenerateAccessToken("session", 1)
 		require.Nil(t, err)
 		require.Equal(t, nil, err)
-		require.NotEqual(t, "token", token)
+		require.NotEqual(t, "", token)
+	})
+
+	t.Run("success : generate random token", func(t *testing.T) {
+		first, err := NewAuth().GenerateRandomToken()
+		require.Nil(t, err)
+
+		second, err := NewAuth().GenerateRandomToken()
+		require.Nil(t, err)
+		require.Len(t, first, 64)
+		require.NotEqual(t, first, second)
+	})
+
+	t.Run("err : refresh token is required", func(t *testing.T) {
+		err := NewAuth().ValidateRefreshToken(dto.RefreshTokenRequest{})
+		require.NotNil(t, err)
+		require.Equal(t, ErrRefreshTokenIsRequired, err)
+	})
+
+	t.Run("err : forgot password email is invalid", func(t *testing.T) {
+		_, err := NewAuth().ValidateForgotPassword(dto.ForgotPasswordRequest{Email: "test"})
+		require.NotNil(t, err)
+		require.Equal(t, ErrEmailIsInvalid, err)
+	})
+
+	t.Run("err : reset token is required", func(t *testing.T) {
+		_, err := NewAuth().ValidateResetPassword(dto.ResetPasswordRequest{Password: "123456"})
+		require.NotNil(t, err)
+		require.Equal(t, ErrResetTokenIsRequired, err)
+	})
+
+	t.Run("err : reset password length", func(t *testing.T) {
+		_, err := NewAuth().ValidateResetPassword(dto.ResetPasswordRequest{Token: "token", Password: "123"})
+		require.NotNil(t, err)
+		require.Equal(t, ErrPasswordLength, err)
+	})
+
+	t.Run("success : validate reset password request", func(t *testing.T) {
+		auth, err := NewAuth().ValidateResetPassword(dto.ResetPasswordRequest{Token: "token", Password: "123456"})
+		require.Nil(t, err)
+		require.Equal(t, "123456", auth.Password)
+	})
+
+	t.Run("err : verify token is required", func(t *testing.T) {
+		err := NewAuth().ValidateVerifyToken("")
+		require.NotNil(t, err)
+		require.Equal(t, ErrVerifyTokenIsRequired, err)
+	})
+
+	t.Run("err : resend verification email is invalid", func(t *testing.T) {
+		_, err := NewAuth().ValidateResendVerification(dto.ResendVerificationRequest{Email: "user"})
+		require.Equal(t, ErrEmailIsInvalid, err)
+	})
+
+	t.Run("err : verification policy is invalid", func(t *testing.T) {
+		require.Equal(t, ErrVerificationPolicyIsInvalid, NewAuth().ValidateVerificationPolicy("logn"))
+		require.Equal(t, ErrVerificationPolicyIsInvalid, NewAuth().ValidateVerificationPolicy(""))
+		require.Nil(t, NewAuth().ValidateVerificationPolicy(VerificationPolicyLogin))
+		require.Nil(t, NewAuth().ValidateVerificationPolicy(VerificationPolicyMerchant))
+	})
+
+	t.Run("err : email is not verified", func(t *testing.T) {
+		err := NewAuth().CheckEmailVerified()
+		require.NotNil(t, err)
+		require.Equal(t, ErrEmailNotVerified, err)
+	})
+
+	t.Run("success : email is verified", func(t *testing.T) {
+		verifiedAt := "2023-12-11T09:00:00Z"
+		err := Auth{EmailVerifiedAt: &verifiedAt}.CheckEmailVerified()
+		require.Nil(t, err)
 	})
 
 	t.Run("err : validate user role", func(t *testing.T) {
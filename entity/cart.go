@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/dto"
+)
+
+var (
+	ErrCartSkuIsRequired     = errors.New("sku is required")
+	ErrCartQuantityIsInvalid = errors.New("quantity is invalid")
+	ErrCartItemNotFound      = errors.New("item not found in cart")
+	ErrCartIsFull            = errors.New("cart is full")
+	ErrCartVariantIsRequired = errors.New("product has variants, use the sku of a variant")
+)
+
+const (
+	MaxCartItems    = 50
+	MaxCartQuantity = 1000
+)
+
+type CartItem struct {
+	Sku      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+	Price    int    `json:"price"`
+	AddedAt  string `json:"added_at"`
+}
+
+func NewCartItem() CartItem {
+	return CartItem{}
+}
+
+func (c CartItem) Validate(req dto.AddCartItemRequest) (CartItem, error) {
+	sku := strings.TrimSpace(req.Sku)
+	if sku == "" {
+		return c, ErrCartSkuIsRequired
+	}
+
+	if req.Quantity <= 0 || req.Quantity > MaxCartQuantity {
+		return c, ErrCartQuantityIsInvalid
+	}
+
+	c.Sku = sku
+	c.Quantity = req.Quantity
+	c.AddedAt = time.Now().UTC().Format(time.RFC3339)
+
+	return c, nil
+}
+
+func (c CartItem) ValidateUpdate(req dto.UpdateCartItemRequest, sku string) (CartItem, error) {
+	return c.Validate(dto.AddCartItemRequest{Sku: sku, Quantity: req.Quantity})
+}
+
+func (c CartItem) CartResponse(items []CartItem, products map[string]Product) dto.GetCartResponse {
+	response := dto.GetCartResponse{Merchants: []dto.CartMerchantResponse{}}
+	positions := map[int]int{}
+
+	for _, item := range items {
+		product, ok := products[item.Sku]
+		if !ok {
+			response.HasChanges = true
+			response.Merchants = appendCartItem(response.Merchants, positions, Product{}, dto.CartItemResponse{
+				Sku:         item.Sku,
+				Quantity:    item.Quantity,
+				Unavailable: true,
+			})
+			continue
+		}
+
+		line := dto.CartItemResponse{
+			Sku:          item.Sku,
+			ProductId:    product.ID,
+			VariantId:    product.VariantId,
+			Name:         product.Name,
+			ImageUrl:     product.ImageUrl,
+			Quantity:     item.Quantity,
+			Price:        product.Price,
+			Stock:        product.Stock,
+			PriceChanged: item.Price != product.Price,
+			OutOfStock:   product.Stock < item.Quantity,
+			HasVariants:  product.VariantCount > 0,
+		}
+
+		if line.PriceChanged {
+			previousPrice := item.Price
+			line.PreviousPrice = &previousPrice
+		}
+
+		if line.PriceChanged || line.OutOfStock || line.HasVariants {
+			response.HasChanges = true
+		}
+
+		if !line.OutOfStock && !line.HasVariants {
+			line.Subtotal = line.Price * line.Quantity
+			response.TotalQuantity += line.Quantity
+			response.TotalPrice += line.Subtotal
+		}
+
+		response.Merchants = appendCartItem(response.Merchants, positions, product, line)
+	}
+
+	return response
+}
+
+func appendCartItem(merchants []dto.CartMerchantResponse, positions map[int]int, product Product, line dto.CartItemResponse) []dto.CartMerchantResponse {
+	position, ok := positions[product.MerchantId]
+	if !ok {
+		position = len(merchants)
+		positions[product.MerchantId] = position
+		merchants = append(merchants, dto.CartMerchantResponse{
+			MerchantId:   product.MerchantId,
+			MerchantName: product.MerchantName,
+			MerchantCity: product.MerchantCity,
+			Items:        []dto.CartItemResponse{},
+		})
+	}
+
+	merchants[position].Items = append(merchants[position].Items, line)
+	merchants[position].Subtotal += line.Subtotal
+
+	return merchants
+}
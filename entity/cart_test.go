@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/ecommerce/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityCartItem(t *testing.T) {
+	t.Run("err : sku is required", func(t *testing.T) {
+		_, err := NewCartItem().Validate(dto.AddCartItemRequest{Sku: " ", Quantity: 1})
+		require.Equal(t, ErrCartSkuIsRequired, err)
+	})
+
+	t.Run("err : quantity is invalid", func(t *testing.T) {
+		for _, quantity := range []int{0, -1, MaxCartQuantity + 1} {
+			_, err := NewCartItem().Validate(dto.AddCartItemRequest{Sku: "SKU-1", Quantity: quantity})
+			require.Equal(t, ErrCartQuantityIsInvalid, err, quantity)
+		}
+	})
+
+	t.Run("success : update takes sku from path", func(t *testing.T) {
+		item, err := NewCartItem().ValidateUpdate(dto.UpdateCartItemRequest{Quantity: 3}, "SKU-1")
+		require.NoError(t, err)
+		require.Equal(t, "SKU-1", item.Sku)
+		require.Equal(t, 3, item.Quantity)
+	})
+
+	t.Run("success : items grouped by merchant", func(t *testing.T) {
+		products := map[string]Product{
+			"A": {ID: 1, MerchantId: 1, Price: 1000, Stock: 10},
+			"B": {ID: 2, MerchantId: 2, Price: 2000, Stock: 10},
+			"C": {ID: 3, MerchantId: 1, Price: 3000, Stock: 1},
+		}
+		items := []CartItem{
+			{Sku: "A", Quantity: 2, Price: 1000},
+			{Sku: "B", Quantity: 1, Price: 2000},
+			{Sku: "C", Quantity: 2, Price: 3000},
+		}
+
+		response := NewCartItem().CartResponse(items, products)
+		require.Len(t, response.Merchants, 2)
+		require.Len(t, response.Merchants[0].Items, 2)
+		require.Equal(t, 2000, response.Merchants[0].Subtotal)
+		require.True(t, response.Merchants[0].Items[1].OutOfStock)
+		require.Equal(t, 4000, response.TotalPrice)
+		require.Equal(t, 3, response.TotalQuantity)
+		require.True(t, response.HasChanges)
+	})
+
+	t.Run("success : item with variants is left out of totals", func(t *testing.T) {
+		response := NewCartItem().CartResponse([]CartItem{{Sku: "A", Quantity: 1, Price: 1000}}, map[string]Product{"A": {Price: 1000, Stock: 5, VariantCount: 2}})
+		line := response.Merchants[0].Items[0]
+		require.True(t, line.HasVariants)
+		require.Zero(t, line.Subtotal)
+		require.Zero(t, response.TotalPrice)
+		require.True(t, response.HasChanges)
+	})
+
+	t.Run("success : price change keeps previous price", func(t *testing.T) {
+		response := NewCartItem().CartResponse([]CartItem{{Sku: "A", Quantity: 1, Price: 900}}, map[string]Product{"A": {Price: 1000, Stock: 1}})
+		line := response.Merchants[0].Items[0]
+		require.True(t, line.PriceChanged)
+		require.Equal(t, 900, *line.PreviousPrice)
+		require.Equal(t, 1000, line.Subtotal)
+	})
+}
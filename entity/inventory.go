@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ecommerce/dto"
+)
+
+var (
+	ErrMovementTypeIsInvalid = errors.New("movement type is invalid")
+	ErrQuantityIsRequired    = errors.New("quantity is required")
+	ErrQuantityIsInvalid     = errors.New("quantity sign does not match the movement type")
+	ErrReasonIsRequired      = errors.New("reason is required")
+	ErrReasonIsInvalid       = errors.New("reason is too long")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrVariantIsRequired     = errors.New("variant id is required for a product with variants")
+	ErrVariantNotFound       = errors.New("variant not found")
+)
+
+const (
+	MovementRestock     = "restock"
+	MovementSale        = "sale"
+	MovementAdjustment  = "adjustment"
+	MovementReturn      = "return"
+	MovementReservation = "reservation"
+)
+
+const MaxReasonLength = 255
+
+type InventoryMovement struct {
+	ID         int    `db:"id"`
+	ProductId  int    `db:"product_id"`
+	VariantId  *int   `db:"variant_id"`
+	Type       string `db:"type"`
+	Quantity   int    `db:"quantity"`
+	StockAfter int    `db:"stock_after"`
+	Reason     string `db:"reason"`
+	CreatedBy  string `db:"created_by"`
+	CreatedAt  string `db:"created_at"`
+	TotalData  int    `db:"total_data"`
+}
+
+func NewInventoryMovement() InventoryMovement {
+	return InventoryMovement{}
+}
+
+func (m InventoryMovement) Validate(req dto.CreateStockAdjustmentRequest, productId int, id string) (InventoryMovement, error) {
+	movementType := strings.ToLower(strings.TrimSpace(req.Type))
+	if movementType == "" {
+		movementType = MovementAdjustment
+	}
+
+	switch movementType {
+	case MovementRestock, MovementReturn:
+		if req.Quantity < 0 {
+			return m, ErrQuantityIsInvalid
+		}
+	case MovementAdjustment:
+		if strings.TrimSpace(req.Reason) == "" {
+			return m, ErrReasonIsRequired
+		}
+	default:
+		return m, ErrMovementTypeIsInvalid
+	}
+
+	if req.Quantity == 0 {
+		return m, ErrQuantityIsRequired
+	}
+
+	if len(req.Reason) > MaxReasonLength {
+		return m, ErrReasonIsInvalid
+	}
+
+	m.ProductId = productId
+	m.VariantId = req.VariantId
+	m.Type = movementType
+	m.Quantity = req.Quantity
+	m.Reason = strings.TrimSpace(req.Reason)
+	m.CreatedBy = id
+
+	return m, nil
+}
+
+func (m InventoryMovement) MovementResponse(movement InventoryMovement) dto.InventoryMovementResponse {
+	return dto.InventoryMovementResponse{
+		ID:         movement.ID,
+		ProductId:  movement.ProductId,
+		VariantId:  movement.VariantId,
+		Type:       movement.Type,
+		Quantity:   movement.Quantity,
+		StockAfter: movement.StockAfter,
+		Reason:     movement.Reason,
+		CreatedBy:  movement.CreatedBy,
+		CreatedAt:  movement.CreatedAt,
+	}
+}
+
+func (m InventoryMovement) MovementHistoryResponse(movements []InventoryMovement) []dto.InventoryMovementResponse {
+	responses := []dto.InventoryMovementResponse{}
+
+	for _, movement := range movements {
+		responses = append(responses, m.MovementResponse(movement))
+	}
+
+	return responses
+}
+
+func (m InventoryMovement) ValidatePage(limit, page int) (int, int, error) {
+	if limit < 0 || page < 0 {
+		return 0, 0, ErrInvalidQueryParam
+	}
+
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	if page == 0 {
+		page = 1
+	}
+
+	return limit, page, nil
+}
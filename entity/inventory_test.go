@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ecommerce/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityInventoryMovement(t *testing.T) {
+	t.Run("err : movement type is invalid", func(t *testing.T) {
+		for _, movementType := range []string{"sale", "reservation", "gift"} {
+			_, err := NewInventoryMovement().Validate(dto.CreateStockAdjustmentRequest{Type: movementType, Quantity: 1}, 1, "1")
+			require.Equal(t, ErrMovementTypeIsInvalid, err, movementType)
+		}
+	})
+
+	t.Run("err : quantity is required", func(t *testing.T) {
+		_, err := NewInventoryMovement().Validate(dto.CreateStockAdjustmentRequest{Type: "restock"}, 1, "1")
+		require.Equal(t, ErrQuantityIsRequired, err)
+	})
+
+	t.Run("err : restock must add stock", func(t *testing.T) {
+		_, err := NewInventoryMovement().Validate(dto.CreateStockAdjustmentRequest{Type: "restock", Quantity: -1}, 1, "1")
+		require.Equal(t, ErrQuantityIsInvalid, err)
+	})
+
+	t.Run("err : adjustment reason is required", func(t *testing.T) {
+		_, err := NewInventoryMovement().Validate(dto.CreateStockAdjustmentRequest{Quantity: -1, Reason: " "}, 1, "1")
+		require.Equal(t, ErrReasonIsRequired, err)
+	})
+
+	t.Run("err : reason is too long", func(t *testing.T) {
+		_, err := NewInventoryMovement().Validate(dto.CreateStockAdjustmentRequest{Quantity: -1, Reason: strings.Repeat("a", MaxReasonLength+1)}, 1, "1")
+		require.Equal(t, ErrReasonIsInvalid, err)
+	})
+
+	t.Run("success : type defaults to adjustment", func(t *testing.T) {
+		variantId := 4
+
+		movement, err := NewInventoryMovement().Validate(dto.CreateStockAdjustmentRequest{VariantId: &variantId, Quantity: -1, Reason: " broken "}, 7, "1")
+		require.Nil(t, err)
+		require.Equal(t, InventoryMovement{
+			ProductId: 7,
+			VariantId: &variantId,
+			Type:      MovementAdjustment,
+			Quantity:  -1,
+			Reason:    "broken",
+			CreatedBy: "1",
+		}, movement)
+	})
+
+	t.Run("success : validate page", func(t *testing.T) {
+		limit, page, err := NewInventoryMovement().ValidatePage(0, 0)
+		require.Nil(t, err)
+		require.Equal(t, DefaultLimit, limit)
+		require.Equal(t, 1, page)
+
+		limit, _, err = NewInventoryMovement().ValidatePage(MaxLimit+1, 1)
+		require.Nil(t, err)
+		require.Equal(t, MaxLimit, limit)
+
+		_, _, err = NewInventoryMovement().ValidatePage(-1, 1)
+		require.Equal(t, ErrInvalidQueryParam, err)
+	})
+}
@@ -1,12 +1,104 @@
 package entity
 
+import (
+	"errors"
+	"regexp"
+
+	"github.com/ecommerce/dto"
+)
+
+var (
+	ErrMerchantNameIsRequired = errors.New("name is required")
+	ErrPhoneNumberIsRequired  = errors.New("phone_number is required")
+	ErrPhoneNumberIsInvalid   = errors.New("phone_number is invalid")
+	ErrPhoneNumberAlreadyUsed = errors.New("phone_number already used")
+	ErrAddressIsRequired      = errors.New("address is required")
+	ErrCityIsRequired         = errors.New("city is required")
+	ErrMerchantNotFound       = errors.New("merchant not found")
+)
+
+var PhoneNumberPattern string = `^\+?[0-9]{8,15}$`
+
 type Merchant struct {
-	ID          int    `db:"id"`
-	Name        string `db:"name"`
-	PhoneNumber string `db:"phone_number"`
-	Address     string `db:"address"`
-	ImageUrl    string `db:"image_url"`
-	City        string `db:"city"`
-	Role        string `db:"role"`
-	CreatedBy   string `db:"created_by"`
+	ID          int     `db:"id"`
+	Name        string  `db:"name"`
+	PhoneNumber string  `db:"phone_number"`
+	Address     string  `db:"address"`
+	ImageUrl    string  `db:"image_url"`
+	City        string  `db:"city"`
+	Role        string  `db:"role"`
+	CreatedBy   string  `db:"created_by"`
+	UpdatedBy   *string `db:"updated_by"`
+	CreatedAt   string  `db:"created_at"`
+	UpdatedAt   *string `db:"updated_at"`
+}
+
+func NewMerchant() Merchant {
+	return Merchant{}
+}
+
+func (m Merchant) Validate(req dto.CreateOrUpdateMerchantRequest, id string) (Merchant, error) {
+	if req.Name == "" {
+		return m, ErrMerchantNameIsRequired
+	}
+
+	if req.PhoneNumber == "" {
+		return m, ErrPhoneNumberIsRequired
+	}
+
+	phoneRegex := regexp.MustCompile(PhoneNumberPattern)
+	if !phoneRegex.MatchString(req.PhoneNumber) {
+		return m, ErrPhoneNumberIsInvalid
+	}
+
+	if req.Address == "" {
+		return m, ErrAddressIsRequired
+	}
+
+	if req.City == "" {
+		return m, ErrCityIsRequired
+	}
+
+	if req.ImageUrl == "" {
+		return m, ErrImageUrlIsRequired
+	}
+
+	m.Name = req.Name
+	m.PhoneNumber = req.PhoneNumber
+	m.Address = req.Address
+	m.City = req.City
+	m.ImageUrl = req.ImageUrl
+	m.CreatedBy = id
+
+	return m, nil
+}
+
+func (m Merchant) MerchantProfileResponse(merchant Merchant) dto.GetMerchantProfileResponse {
+	response := dto.GetMerchantProfileResponse{
+		ID:          merchant.ID,
+		Name:        merchant.Name,
+		PhoneNumber: merchant.PhoneNumber,
+		Address:     merchant.Address,
+		City:        merchant.City,
+		ImageUrl:    merchant.ImageUrl,
+		CreatedAt:   merchant.CreatedAt,
+		UpdatedAt:   NewProduct().NullStringScan(merchant.UpdatedAt),
+	}
+
+	return response
+}
+
+func (m Merchant) MerchantStorefrontResponse(merchant Merchant, products []Product) dto.GetMerchantStorefrontResponse {
+	response := dto.GetMerchantStorefrontResponse{
+		Merchant: dto.Merchant{
+			ID:   merchant.ID,
+			Name: merchant.Name,
+			City: merchant.City,
+		},
+		Address:  merchant.Address,
+		ImageUrl: merchant.ImageUrl,
+		Products: NewProduct().ProductResponse(products),
+	}
+
+	return response
 }
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/ecommerce/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityMerchant(t *testing.T) {
+	t.Run("err : merchant name is required", func(t *testing.T) {
+		var req = dto.CreateOrUpdateMerchantRequest{
+			Name:        "",
+			PhoneNumber: "081234567890",
+			Address:     "test",
+			City:        "test",
+			ImageUrl:    "test",
+		}
+
+		_, err := NewMerchant().Validate(req, "1")
+		require.NotNil(t, err)
+		require.Equal(t, ErrMerchantNameIsRequired, err)
+	})
+
+	t.Run("err : phone number is required", func(t *testing.T) {
+		var req = dto.CreateOrUpdateMerchantRequest{
+			Name:        "test",
+			PhoneNumber: "",
+			Address:     "test",
+			City:        "test",
+			ImageUrl:    "test",
+		}
+
+		_, err := NewMerchant().Validate(req, "1")
+		require.NotNil(t, err)
+		require.Equal(t, ErrPhoneNumberIsRequired, err)
+	})
+
+	t.Run("err : phone number is invalid", func(t *testing.T) {
+		var req = dto.CreateOrUpdateMerchantRequest{
+			Name:        "test",
+			PhoneNumber: "0812-abc",
+			Address:     "test",
+			City:        "test",
+			ImageUrl:    "test",
+		}
+
+		_, err := NewMerchant().Validate(req, "1")
+		require.NotNil(t, err)
+		require.Equal(t, ErrPhoneNumberIsInvalid, err)
+	})
+
+	t.Run("err : address is required", func(t *testing.T) {
+		var req = dto.CreateOrUpdateMerchantRequest{
+			Name:        "test",
+			PhoneNumber: "081234567890",
+			Address:     "",
+			City:        "test",
+			ImageUrl:    "test",
+		}
+
+		_, err := NewMerchant().Validate(req, "1")
+		require.NotNil(t, err)
+		require.Equal(t, ErrAddressIsRequired, err)
+	})
+
+	t.Run("err : city is required", func(t *testing.T) {
+		var req = dto.CreateOrUpdateMerchantRequest{
+			Name:        "test",
+			PhoneNumber: "081234567890",
+			Address:     "test",
+			City:        "",
+			ImageUrl:    "test",
+		}
+
+		_, err := NewMerchant().Validate(req, "1")
+		require.NotNil(t, err)
+		require.Equal(t, ErrCityIsRequired, err)
+	})
+
+	t.Run("err : image url is required", func(t *testing.T) {
+		var req = dto.CreateOrUpdateMerchantRequest{
+			Name:        "test",
+			PhoneNumber: "081234567890",
+			Address:     "test",
+			City:        "test",
+			ImageUrl:    "",
+		}
+
+		_, err := NewMerchant().Validate(req, "1")
+		require.NotNil(t, err)
+		require.Equal(t, ErrImageUrlIsRequired, err)
+	})
+
+	t.Run("success : validate merchant request", func(t *testing.T) {
+		var req = dto.CreateOrUpdateMerchantRequest{
+			Name:        "test",
+			PhoneNumber: "+6281234567890",
+			Address:     "test",
+			City:        "test",
+			ImageUrl:    "test",
+		}
+
+		merchant, err := NewMerchant().Validate(req, "1")
+		require.Nil(t, err)
+		require.Equal(t, "1", merchant.CreatedBy)
+	})
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/ecommerce/dto"
+)
+
+const NotificationLowStock = "low_stock"
+
+type Notification struct {
+	ID            int     `db:"id"`
+	MerchantId    int     `db:"merchant_id"`
+	MerchantEmail string  `db:"merchant_email"`
+	ProductId     int     `db:"product_id"`
+	ProductName   string  `db:"product_name"`
+	Type          string  `db:"type"`
+	Title         string  `db:"title"`
+	Message       string  `db:"message"`
+	Stock         int     `db:"stock"`
+	Threshold     int     `db:"threshold"`
+	ReadAt        *string `db:"read_at"`
+	CreatedAt     string  `db:"created_at"`
+	TotalData     int     `db:"total_data"`
+}
+
+func NewNotification() Notification {
+	return Notification{}
+}
+
+func (n Notification) LowStock(notification Notification) Notification {
+	notification.Type = NotificationLowStock
+	notification.Title = fmt.Sprintf("Low stock: %s", notification.ProductName)
+	notification.Message = fmt.Sprintf("%s has %d left in stock, below your threshold of %d.",
+		notification.ProductName, notification.Stock, notification.Threshold)
+
+	return notification
+}
+
+func (n Notification) ValidatePage(limit, page int) (int, int, error) {
+	return NewInventoryMovement().ValidatePage(limit, page)
+}
+
+func (n Notification) AlertResponse(notifications []Notification) []dto.GetMerchantAlertResponse {
+	responses := []dto.GetMerchantAlertResponse{}
+
+	for _, notification := range notifications {
+		responses = append(responses, dto.GetMerchantAlertResponse{
+			ID:        notification.ID,
+			ProductId: notification.ProductId,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Message:   notification.Message,
+			Stock:     notification.Stock,
+			Threshold: notification.Threshold,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+
+	return responses
+}
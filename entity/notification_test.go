@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityNotification(t *testing.T) {
+	t.Run("success : low stock alert text", func(t *testing.T) {
+		notification := NewNotification().LowStock(Notification{ProductName: "Kopi", Stock: 2, Threshold: 5})
+		require.Equal(t, NotificationLowStock, notification.Type)
+		require.Equal(t, "Low stock: Kopi", notification.Title)
+		require.Equal(t, "Kopi has 2 left in stock, below your threshold of 5.", notification.Message)
+	})
+
+	t.Run("success : alert response keeps order", func(t *testing.T) {
+		response := NewNotification().AlertResponse([]Notification{{ID: 2}, {ID: 1}})
+		require.Len(t, response, 2)
+		require.Equal(t, 2, response[0].ID)
+		require.Equal(t, 1, response[1].ID)
+	})
+
+	t.Run("success : empty alert response", func(t *testing.T) {
+		require.Equal(t, 0, len(NewNotification().AlertResponse(nil)))
+		require.NotNil(t, NewNotification().AlertResponse(nil))
+	})
+}
@@ -1 +1,225 @@
 package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/dto"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOrderItemsIsRequired = errors.New("items is required")
+	ErrOrderItemIsDuplicate = errors.New("item sku is duplicate")
+	ErrOrderPriceChanged    = errors.New("price changed since the item was added to the cart")
+	ErrOrderTooManyItems    = errors.New("too many items")
+	ErrPaymentUnavailable   = errors.New("payment is unavailable, try again later")
+	ErrPaymentMismatch      = errors.New("payment does not match the order")
+)
+
+type Order struct {
+	ID         string `db:"id"`
+	UserId     string `db:"user_id"`
+	MerchantId int    `db:"merchant_id"`
+	TrxId      string `db:"trx_id"`
+	TotalPrice int    `db:"total_price"`
+	Status     string `db:"status"`
+	InvoiceUrl string `db:"invoice_url"`
+	CreatedBy  string `db:"created_by"`
+
+	PaidAfterCloseAt *string `db:"paid_after_close_at"`
+	PaymentExpiresAt *string `db:"payment_expires_at"`
+	CreatedAt        string  `db:"created_at"`
+	UpdatedAt        *string `db:"updated_at"`
+
+	Details []OrderDetail `db:"-"`
+
+	FromCart bool `db:"-"`
+}
+
+type OrderDetail struct {
+	ID                string `db:"id"`
+	OrderId           string `db:"order_id"`
+	ProductId         int    `db:"product_id"`
+	VariantId         *int   `db:"variant_id"`
+	Sku               string `db:"sku"`
+	ProductName       string `db:"product_name"`
+	Price             int    `db:"price"`
+	Quantity          int    `db:"quantity"`
+	TotalPriceProduct int    `db:"total_price_product"`
+	CreatedBy         string `db:"created_by"`
+	CreatedAt         string `db:"created_at"`
+
+	MerchantId int `db:"-"`
+}
+
+func NewOrder() Order {
+	return Order{}
+}
+
+func (o Order) ValidateCheckout(req dto.CheckoutRequest, id string) (Order, error) {
+	if len(req.Items) > MaxCartItems {
+		return o, ErrOrderTooManyItems
+	}
+
+	seen := map[string]bool{}
+	details := []OrderDetail{}
+	for _, item := range req.Items {
+		sku := strings.TrimSpace(item.Sku)
+		if sku == "" {
+			return o, ErrCartSkuIsRequired
+		}
+
+		if item.Quantity <= 0 || item.Quantity > MaxCartQuantity {
+			return o, ErrCartQuantityIsInvalid
+		}
+
+		if seen[sku] {
+			return o, ErrOrderItemIsDuplicate
+		}
+		seen[sku] = true
+
+		details = append(details, OrderDetail{Sku: sku, Quantity: item.Quantity})
+	}
+
+	return o.newOrder(details, id), nil
+}
+
+func (o Order) ValidateCart(items []CartItem, id string) (Order, error) {
+	if len(items) == 0 {
+		return o, ErrOrderItemsIsRequired
+	}
+
+	details := []OrderDetail{}
+	for _, item := range items {
+		details = append(details, OrderDetail{Sku: item.Sku, Quantity: item.Quantity, Price: item.Price})
+	}
+
+	order := o.newOrder(details, id)
+	order.FromCart = true
+
+	return order, nil
+}
+
+func (o Order) newOrder(details []OrderDetail, id string) Order {
+	o.ID = uuid.New().String()
+	o.UserId = id
+	o.TrxId = fmt.Sprintf("TRX-%s-%s", time.Now().UTC().Format("20060102"), strings.ToUpper(strings.ReplaceAll(o.ID, "-", "")[:12]))
+	o.Status = OrderStatusPendingPayment
+	o.CreatedBy = id
+
+	for i := range details {
+		details[i].OrderId = o.ID
+		details[i].CreatedBy = id
+	}
+	o.Details = details
+
+	return o
+}
+
+func (o Order) PriceDetail(order Order, index int, product Product) (Order, error) {
+	detail := order.Details[index]
+
+	if product.VariantCount > 0 {
+		return order, ErrCartVariantIsRequired
+	}
+
+	if order.FromCart && detail.Price != product.Price {
+		return order, ErrOrderPriceChanged
+	}
+
+	if product.Stock < detail.Quantity {
+		return order, ErrInsufficientStock
+	}
+
+	detail.ProductId = product.ID
+	detail.VariantId = product.VariantId
+	detail.MerchantId = product.MerchantId
+	detail.ProductName = product.Name
+	detail.Price = product.Price
+	detail.TotalPriceProduct = product.Price * detail.Quantity
+	order.Details[index] = detail
+	order.TotalPrice += detail.TotalPriceProduct
+
+	return order, nil
+}
+
+func (o Order) SplitByMerchant(order Order) []Order {
+	orders := []Order{}
+	indexes := map[int]int{}
+
+	for _, detail := range order.Details {
+		i, ok := indexes[detail.MerchantId]
+		if !ok {
+			i = len(orders)
+			indexes[detail.MerchantId] = i
+			orders = append(orders, Order{MerchantId: detail.MerchantId, FromCart: order.FromCart})
+		}
+
+		orders[i].Details = append(orders[i].Details, detail)
+		orders[i].TotalPrice += detail.TotalPriceProduct
+	}
+
+	for i := range orders {
+		orders[i] = orders[i].newOrder(orders[i].Details, order.UserId)
+	}
+
+	return orders
+}
+
+func (o Order) IsPaid() bool {
+	switch o.Status {
+	case OrderStatusPaid, OrderStatusProcessing, OrderStatusShipped, OrderStatusDelivered, OrderStatusCompleted, OrderStatusRefunded:
+		return true
+	}
+
+	return false
+}
+
+func (o Order) IsClosedUnpaid() bool {
+	return o.Status == OrderStatusCancelled || o.Status == OrderStatusExpired
+}
+
+func (o Order) OrderResponse(order Order) dto.GetOrderResponse {
+	response := dto.GetOrderResponse{
+		ID:               order.ID,
+		MerchantId:       order.MerchantId,
+		TrxId:            order.TrxId,
+		Status:           order.Status,
+		TotalPrice:       order.TotalPrice,
+		InvoiceUrl:       order.InvoiceUrl,
+		PaidAfterCloseAt: order.PaidAfterCloseAt,
+		PaymentExpiresAt: order.PaymentExpiresAt,
+		Items:            []dto.GetOrderDetailResponse{},
+		CreatedAt:        order.CreatedAt,
+	}
+
+	for _, detail := range order.Details {
+		response.Items = append(response.Items, dto.GetOrderDetailResponse{
+			ProductId:         detail.ProductId,
+			VariantId:         detail.VariantId,
+			Sku:               detail.Sku,
+			Name:              detail.ProductName,
+			Price:             detail.Price,
+			Quantity:          detail.Quantity,
+			TotalPriceProduct: detail.TotalPriceProduct,
+		})
+	}
+
+	return response
+}
+
+func (o Order) CheckoutResponse(orders []Order) dto.CheckoutResponse {
+	response := dto.CheckoutResponse{
+		Orders: []dto.GetOrderResponse{},
+	}
+
+	for _, order := range orders {
+		response.Orders = append(response.Orders, o.OrderResponse(order))
+		response.TotalPrice += order.TotalPrice
+	}
+
+	return response
+}
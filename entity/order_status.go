@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ecommerce/dto"
+)
+
+var (
+	ErrOrderNotFound            = errors.New("order not found")
+	ErrOrderStatusIsInvalid     = errors.New("order status is invalid")
+	ErrOrderStatusIsFinal       = errors.New("order is already closed")
+	ErrOrderTransitionIsInvalid = errors.New("order cannot move to this status")
+	ErrOrderTransitionForbidden = errors.New("not allowed to move the order to this status")
+	ErrOrderStatusConflict      = errors.New("order status was changed by another request")
+)
+
+const (
+	OrderStatusPendingPayment = "PENDING_PAYMENT"
+	OrderStatusPaid           = "PAID"
+	OrderStatusProcessing     = "PROCESSING"
+	OrderStatusShipped        = "SHIPPED"
+	OrderStatusDelivered      = "DELIVERED"
+	OrderStatusCompleted      = "COMPLETED"
+	OrderStatusCancelled      = "CANCELLED"
+	OrderStatusRefunded       = "REFUNDED"
+	OrderStatusExpired        = "EXPIRED"
+)
+
+const (
+	ActorBuyer    = "buyer"
+	ActorMerchant = "merchant"
+	ActorAdmin    = "admin"
+	ActorSystem   = "system"
+)
+
+var orderTransitions = map[string]map[string][]string{
+	OrderStatusPendingPayment: {
+		OrderStatusPaid:      {ActorSystem, ActorAdmin},
+		OrderStatusCancelled: {ActorBuyer, ActorAdmin, ActorSystem},
+		OrderStatusExpired:   {ActorSystem},
+	},
+	OrderStatusPaid: {
+		OrderStatusProcessing: {ActorMerchant, ActorAdmin},
+		OrderStatusRefunded:   {ActorMerchant, ActorAdmin},
+	},
+	OrderStatusProcessing: {
+		OrderStatusShipped:  {ActorMerchant, ActorAdmin},
+		OrderStatusRefunded: {ActorMerchant, ActorAdmin},
+	},
+	OrderStatusShipped: {
+		OrderStatusDelivered: {ActorMerchant, ActorAdmin},
+	},
+	OrderStatusDelivered: {
+		OrderStatusCompleted: {ActorBuyer, ActorAdmin},
+		OrderStatusRefunded:  {ActorMerchant, ActorAdmin},
+	},
+	OrderStatusCompleted: {},
+	OrderStatusCancelled: {},
+	OrderStatusRefunded:  {},
+	OrderStatusExpired:   {},
+}
+
+type OrderStatusHistory struct {
+	ID         int     `db:"id"`
+	OrderId    string  `db:"order_id"`
+	FromStatus *string `db:"from_status"`
+	ToStatus   string  `db:"to_status"`
+	ActorId    *string `db:"actor_id"`
+	Actor      string  `db:"actor"`
+	Reason     string  `db:"reason"`
+	CreatedAt  string  `db:"created_at"`
+}
+
+func NewOrderStatusHistory() OrderStatusHistory {
+	return OrderStatusHistory{}
+}
+
+func (h OrderStatusHistory) Validate(req dto.UpdateOrderStatusRequest, orderId, id string) (OrderStatusHistory, error) {
+	status := strings.ToUpper(strings.TrimSpace(req.Status))
+	if _, ok := orderTransitions[status]; !ok {
+		return h, ErrOrderStatusIsInvalid
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > MaxReasonLength {
+		return h, ErrReasonIsInvalid
+	}
+
+	h.OrderId = orderId
+	h.ToStatus = status
+	h.Reason = reason
+	if id != "" {
+		h.ActorId = &id
+	}
+
+	return h, nil
+}
+
+func (h OrderStatusHistory) Transition(order Order, history OrderStatusHistory, actor string) (OrderStatusHistory, error) {
+	next := orderTransitions[order.Status]
+	if len(next) == 0 {
+		return history, ErrOrderStatusIsFinal
+	}
+
+	actors, ok := next[history.ToStatus]
+	if !ok {
+		return history, ErrOrderTransitionIsInvalid
+	}
+
+	allowed := false
+	for _, candidate := range actors {
+		if candidate == actor {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return history, ErrOrderTransitionForbidden
+	}
+
+	from := order.Status
+	history.OrderId = order.ID
+	history.FromStatus = &from
+	history.Actor = actor
+
+	return history, nil
+}
+
+func (h OrderStatusHistory) ReleasesStock() bool {
+	if h.FromStatus == nil {
+		return false
+	}
+
+	switch h.ToStatus {
+	case OrderStatusCancelled, OrderStatusExpired:
+		return true
+	case OrderStatusRefunded:
+		return *h.FromStatus == OrderStatusPaid || *h.FromStatus == OrderStatusProcessing
+	}
+
+	return false
+}
+
+func (h OrderStatusHistory) ClosesInvoice() bool {
+	if h.FromStatus == nil || *h.FromStatus != OrderStatusPendingPayment {
+		return false
+	}
+
+	return h.ToStatus == OrderStatusCancelled || h.ToStatus == OrderStatusExpired
+}
+
+func (h OrderStatusHistory) HistoryResponse(histories []OrderStatusHistory) []dto.OrderStatusHistoryResponse {
+	responses := []dto.OrderStatusHistoryResponse{}
+
+	for _, history := range histories {
+		responses = append(responses, dto.OrderStatusHistoryResponse{
+			FromStatus: history.FromStatus,
+			ToStatus:   history.ToStatus,
+			Actor:      history.Actor,
+			Reason:     history.Reason,
+			CreatedAt:  history.CreatedAt,
+		})
+	}
+
+	return responses
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ecommerce/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityOrderStatus(t *testing.T) {
+	t.Run("err : status is invalid", func(t *testing.T) {
+		_, err := NewOrderStatusHistory().Validate(dto.UpdateOrderStatusRequest{Status: "lost"}, "order-1", "user-id")
+		require.Equal(t, ErrOrderStatusIsInvalid, err)
+	})
+
+	t.Run("err : reason is too long", func(t *testing.T) {
+		_, err := NewOrderStatusHistory().Validate(dto.UpdateOrderStatusRequest{Status: "shipped", Reason: strings.Repeat("a", MaxReasonLength+1)}, "order-1", "user-id")
+		require.Equal(t, ErrReasonIsInvalid, err)
+	})
+
+	t.Run("success : status is normalized", func(t *testing.T) {
+		history, err := NewOrderStatusHistory().Validate(dto.UpdateOrderStatusRequest{Status: " shipped "}, "order-1", "user-id")
+		require.NoError(t, err)
+		require.Equal(t, OrderStatusShipped, history.ToStatus)
+		require.Equal(t, "user-id", *history.ActorId)
+	})
+
+	t.Run("success : happy path runs to completed", func(t *testing.T) {
+		steps := []struct{ to, actor string }{
+			{OrderStatusPaid, ActorSystem},
+			{OrderStatusProcessing, ActorMerchant},
+			{OrderStatusShipped, ActorMerchant},
+			{OrderStatusDelivered, ActorMerchant},
+			{OrderStatusCompleted, ActorBuyer},
+		}
+
+		order := Order{ID: "order-1", Status: OrderStatusPendingPayment}
+		for _, step := range steps {
+			history, err := NewOrderStatusHistory().Transition(order, OrderStatusHistory{ToStatus: step.to}, step.actor)
+			require.NoError(t, err, step.to)
+			require.Equal(t, order.Status, *history.FromStatus)
+			order.Status = history.ToStatus
+		}
+	})
+
+	t.Run("err : illegal moves", func(t *testing.T) {
+		_, err := NewOrderStatusHistory().Transition(Order{Status: OrderStatusPendingPayment}, OrderStatusHistory{ToStatus: OrderStatusShipped}, ActorAdmin)
+		require.Equal(t, ErrOrderTransitionIsInvalid, err)
+
+		_, err = NewOrderStatusHistory().Transition(Order{Status: OrderStatusCompleted}, OrderStatusHistory{ToStatus: OrderStatusRefunded}, ActorAdmin)
+		require.Equal(t, ErrOrderStatusIsFinal, err)
+
+		_, err = NewOrderStatusHistory().Transition(Order{Status: OrderStatusPendingPayment}, OrderStatusHistory{ToStatus: OrderStatusExpired}, ActorAdmin)
+		require.Equal(t, ErrOrderTransitionForbidden, err)
+	})
+
+	t.Run("success : stock is released before shipping only", func(t *testing.T) {
+		release := func(from, to string) bool {
+			return OrderStatusHistory{FromStatus: &from, ToStatus: to}.ReleasesStock()
+		}
+
+		require.True(t, release(OrderStatusPendingPayment, OrderStatusCancelled))
+		require.True(t, release(OrderStatusPendingPayment, OrderStatusExpired))
+		require.True(t, release(OrderStatusProcessing, OrderStatusRefunded))
+		require.False(t, release(OrderStatusDelivered, OrderStatusRefunded))
+		require.False(t, release(OrderStatusPaid, OrderStatusProcessing))
+	})
+
+	t.Run("success : invoice is closed with an unpaid order only", func(t *testing.T) {
+		closes := func(from, to string) bool {
+			return OrderStatusHistory{FromStatus: &from, ToStatus: to}.ClosesInvoice()
+		}
+
+		require.True(t, closes(OrderStatusPendingPayment, OrderStatusCancelled))
+		require.True(t, closes(OrderStatusPendingPayment, OrderStatusExpired))
+		require.False(t, closes(OrderStatusPendingPayment, OrderStatusPaid))
+		require.False(t, closes(OrderStatusPaid, OrderStatusCancelled))
+		require.False(t, OrderStatusHistory{ToStatus: OrderStatusCancelled}.ClosesInvoice())
+	})
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ecommerce/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityOrder(t *testing.T) {
+	t.Run("err : sku is duplicate", func(t *testing.T) {
+		_, err := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: []dto.CheckoutItemRequest{
+			{Sku: "A", Quantity: 1},
+			{Sku: " A ", Quantity: 2},
+		}}, "user-id")
+		require.Equal(t, ErrOrderItemIsDuplicate, err)
+	})
+
+	t.Run("err : quantity is invalid", func(t *testing.T) {
+		_, err := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: []dto.CheckoutItemRequest{{Sku: "A"}}}, "user-id")
+		require.Equal(t, ErrCartQuantityIsInvalid, err)
+	})
+
+	t.Run("err : too many items", func(t *testing.T) {
+		_, err := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: make([]dto.CheckoutItemRequest, MaxCartItems+1)}, "user-id")
+		require.Equal(t, ErrOrderTooManyItems, err)
+	})
+
+	t.Run("err : empty cart", func(t *testing.T) {
+		_, err := NewOrder().ValidateCart(nil, "user-id")
+		require.Equal(t, ErrOrderItemsIsRequired, err)
+	})
+
+	t.Run("success : new order is pending payment", func(t *testing.T) {
+		order, err := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: []dto.CheckoutItemRequest{{Sku: "A", Quantity: 2}}}, "user-id")
+		require.NoError(t, err)
+		require.Equal(t, OrderStatusPendingPayment, order.Status)
+		require.Equal(t, "user-id", order.UserId)
+		require.True(t, strings.HasPrefix(order.TrxId, "TRX-"))
+		require.Equal(t, order.ID, order.Details[0].OrderId)
+		require.False(t, order.FromCart)
+	})
+
+	t.Run("success : price detail adds to total", func(t *testing.T) {
+		order, _ := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: []dto.CheckoutItemRequest{
+			{Sku: "A", Quantity: 2},
+			{Sku: "B", Quantity: 1},
+		}}, "user-id")
+
+		order, err := NewOrder().PriceDetail(order, 0, Product{ID: 1, Name: "Kopi", Price: 1000, Stock: 2})
+		require.NoError(t, err)
+		order, err = NewOrder().PriceDetail(order, 1, Product{ID: 2, Name: "Teh", Price: 500, Stock: 9})
+		require.NoError(t, err)
+
+		require.Equal(t, 2000, order.Details[0].TotalPriceProduct)
+		require.Equal(t, 2500, order.TotalPrice)
+	})
+
+	t.Run("success : variant line keeps the variant id and price", func(t *testing.T) {
+		order, _ := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: []dto.CheckoutItemRequest{{Sku: "A-M", Quantity: 2}}}, "user-id")
+
+		variantId := 4
+		order, err := NewOrder().PriceDetail(order, 0, Product{ID: 1, Sku: "A-M", Name: "Kaos", Price: 1500, Stock: 3, VariantId: &variantId})
+		require.NoError(t, err)
+
+		require.Equal(t, &variantId, order.Details[0].VariantId)
+		require.Equal(t, 1500, order.Details[0].Price)
+		require.Equal(t, 3000, order.TotalPrice)
+	})
+
+	t.Run("success : split by merchant", func(t *testing.T) {
+		order, _ := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: []dto.CheckoutItemRequest{
+			{Sku: "A", Quantity: 2},
+			{Sku: "B", Quantity: 1},
+			{Sku: "C", Quantity: 1},
+		}}, "user-id")
+
+		order, _ = NewOrder().PriceDetail(order, 0, Product{ID: 1, MerchantId: 7, Price: 1000, Stock: 2})
+		order, _ = NewOrder().PriceDetail(order, 1, Product{ID: 2, MerchantId: 8, Price: 500, Stock: 9})
+		order, _ = NewOrder().PriceDetail(order, 2, Product{ID: 3, MerchantId: 7, Price: 300, Stock: 9})
+
+		orders := NewOrder().SplitByMerchant(order)
+		require.Len(t, orders, 2)
+
+		require.Equal(t, 7, orders[0].MerchantId)
+		require.Equal(t, 2300, orders[0].TotalPrice)
+		require.Len(t, orders[0].Details, 2)
+		require.Equal(t, orders[0].ID, orders[0].Details[1].OrderId)
+
+		require.Equal(t, 8, orders[1].MerchantId)
+		require.Equal(t, 500, orders[1].TotalPrice)
+		require.Equal(t, "user-id", orders[1].UserId)
+		require.Equal(t, OrderStatusPendingPayment, orders[1].Status)
+		require.NotEqual(t, orders[0].TrxId, orders[1].TrxId)
+	})
+
+	t.Run("err : price detail rejects short stock and variants", func(t *testing.T) {
+		order, _ := NewOrder().ValidateCheckout(dto.CheckoutRequest{Items: []dto.CheckoutItemRequest{{Sku: "A", Quantity: 2}}}, "user-id")
+
+		_, err := NewOrder().PriceDetail(order, 0, Product{Price: 1000, Stock: 1})
+		require.Equal(t, ErrInsufficientStock, err)
+
+		_, err = NewOrder().PriceDetail(order, 0, Product{Price: 1000, Stock: 5, VariantCount: 2})
+		require.Equal(t, ErrCartVariantIsRequired, err)
+	})
+
+	t.Run("err : cart price changed", func(t *testing.T) {
+		order, err := NewOrder().ValidateCart([]CartItem{{Sku: "A", Quantity: 1, Price: 900}}, "user-id")
+		require.NoError(t, err)
+		require.True(t, order.FromCart)
+
+		_, err = NewOrder().PriceDetail(order, 0, Product{Price: 1000, Stock: 5})
+		require.Equal(t, ErrOrderPriceChanged, err)
+	})
+}
@@ -2,42 +2,101 @@ package entity
 
 import (
 	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
 
 	"github.com/ecommerce/dto"
 	"github.com/google/uuid"
 )
 
 var (
-	ErrProductNameIsRequired = errors.New("name is required")
-	ErrDescriptionIsRequired = errors.New("description is required")
-	ErrPriceIsRequired       = errors.New("price is required")
-	ErrPriceIsInvalid        = errors.New("price is invalid")
-	ErrStockIsRequired       = errors.New("stock is required")
-	ErrStockIsInvalid        = errors.New("stock is invalid")
-	ErrCategoryIdIsRequired  = errors.New("category_id is required")
-	ErrImageUrlIsRequired    = errors.New("image_url is required")
-	ErrInvalidRole           = errors.New("invalid role")
-	ErrCategoryNotFound      = errors.New("category_id is not found")
-	ErrProductNotFound       = errors.New("product not found in this resources")
+	ErrProductNameIsRequired      = errors.New("name is required")
+	ErrDescriptionIsRequired      = errors.New("description is required")
+	ErrPriceIsRequired            = errors.New("price is required")
+	ErrPriceIsInvalid             = errors.New("price is invalid")
+	ErrStockIsRequired            = errors.New("stock is required")
+	ErrStockIsInvalid             = errors.New("stock is invalid")
+	ErrLowStockThresholdIsInvalid = errors.New("low stock threshold is invalid")
+	ErrCategoryIdIsRequired       = errors.New("category_id is required")
+	ErrImageUrlIsRequired         = errors.New("image_url is required")
+	ErrCategoryNotFound           = errors.New("category_id is not found")
+	ErrProductNotFound            = errors.New("product not found in this resources")
+	ErrInvalidQueryParam          = errors.New("query param is invalid")
+	ErrSortIsInvalid              = errors.New("sort is invalid")
+	ErrPriceRangeIsInvalid        = errors.New("price range is invalid")
+	ErrSearchQueryIsRequired      = errors.New("query is required")
+	ErrNoFieldsToUpdate           = errors.New("no fields to update")
+	ErrVersionMismatch            = errors.New("product was modified by another request")
 )
 
+const (
+	SortNewest    = "newest"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortNameAsc   = "name_asc"
+	SortNameDesc  = "name_desc"
+	SortRelevance = "relevance"
+
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+type ProductPatch struct {
+	Version           int
+	Name              *string
+	Description       *string
+	Price             *int
+	Stock             *int
+	CategoryId        *int
+	ImageUrl          *string
+	LowStockThreshold *int
+}
+
+type CatalogFilter struct {
+	Query      string
+	CategoryId int
+	MinPrice   int
+	MaxPrice   int
+	City       string
+	InStock    bool
+	Sort       string
+	Limit      int
+	Page       int
+}
+
 type Product struct {
-	ID           int     `db:"id"`
-	Name         string  `db:"name"`
-	Description  string  `db:"description"`
-	Price        int     `db:"price"`
-	Stock        int     `db:"stock"`
-	CategoryId   int     `db:"category_id"`
-	MerchantId   int     `db:"merchant_id"`
-	ImageUrl     string  `db:"image_url"`
-	Sku          string  `db:"sku"`
-	Category     string  `db:"category"`
-	MerchantName string  `db:"merchant_name"`
-	MerchantCity string  `db:"merchant_city"`
-	TotalData    int     `db:"total_data"`
-	CreatedBy    string  `db:"created_by"`
-	CreatedAt    string  `db:"created_at"`
-	UpdatedAt    *string `db:"updated_at"`
+	ID                int     `db:"id"`
+	Name              string  `db:"name"`
+	Description       string  `db:"description"`
+	Price             int     `db:"price"`
+	Stock             int     `db:"stock"`
+	CategoryId        int     `db:"category_id"`
+	MerchantId        int     `db:"merchant_id"`
+	ImageUrl          string  `db:"image_url"`
+	Sku               string  `db:"sku"`
+	Category          string  `db:"category"`
+	MerchantName      string  `db:"merchant_name"`
+	MerchantCity      string  `db:"merchant_city"`
+	TotalData         int     `db:"total_data"`
+	Rank              float64 `db:"rank"`
+	Snippet           string  `db:"snippet"`
+	NameHighlight     string  `db:"name_highlight"`
+	CreatedBy         string  `db:"created_by"`
+	UpdatedBy         *string `db:"updated_by"`
+	Version           int     `db:"version"`
+	VariantCount      int     `db:"variant_count"`
+	LowStockThreshold int     `db:"low_stock_threshold"`
+	CreatedAt         string  `db:"created_at"`
+	UpdatedAt         *string `db:"updated_at"`
+
+	Images   []ProductImage   `db:"-"`
+	Options  []ProductOption  `db:"-"`
+	Variants []ProductVariant `db:"-"`
+
+	ReplaceVariants bool `db:"-"`
+	VariantId       *int `db:"variant_id"`
 }
 
 func NewProduct() Product {
@@ -61,7 +120,9 @@ func (p Product) Validate(req dto.CreateOrUpdateProductRequest, id string) (Prod
 		return p, ErrPriceIsInvalid
 	}
 
-	if req.Stock == 0 {
+	hasVariants := len(req.Options) > 0 || len(req.Variants) > 0
+
+	if !hasVariants && req.Stock == 0 {
 		return p, ErrStockIsRequired
 	}
 
@@ -77,25 +138,155 @@ func (p Product) Validate(req dto.CreateOrUpdateProductRequest, id string) (Prod
 		return p, ErrImageUrlIsRequired
 	}
 
+	if req.LowStockThreshold < 0 {
+		return p, ErrLowStockThresholdIsInvalid
+	}
+
+	options, variants, err := p.ValidateVariants(req.Options, req.Variants)
+	if err != nil {
+		return p, err
+	}
+
 	p.ID = req.ID
 	p.Name = req.Name
 	p.Description = req.Description
 	p.Price = req.Price
 	p.Stock = req.Stock
+	if hasVariants {
+		p.Stock = p.VariantStock(variants)
+	}
 	p.CategoryId = req.CategoryId
 	p.ImageUrl = req.ImageUrl
+	p.LowStockThreshold = req.LowStockThreshold
 	p.Sku = uuid.New().String()
+	p.Options = options
+	p.Variants = variants
+	p.ReplaceVariants = req.Options != nil || req.Variants != nil
 	p.CreatedBy = id
 
 	return p, nil
 }
 
-func (p Product) CheckUserRole(role string) (err error) {
-	if role != "merchant" {
-		return ErrInvalidRole
+func (p Product) ValidatePatch(req dto.UpdateProductRequest) (ProductPatch, error) {
+	if req.Name == nil && req.Description == nil && req.Price == nil &&
+		req.Stock == nil && req.CategoryId == nil && req.ImageUrl == nil && req.LowStockThreshold == nil {
+		return ProductPatch{}, ErrNoFieldsToUpdate
 	}
 
-	return
+	if req.Name != nil && *req.Name == "" {
+		return ProductPatch{}, ErrProductNameIsRequired
+	}
+
+	if req.Description != nil && *req.Description == "" {
+		return ProductPatch{}, ErrDescriptionIsRequired
+	}
+
+	if req.Price != nil {
+		if *req.Price == 0 {
+			return ProductPatch{}, ErrPriceIsRequired
+		}
+
+		if *req.Price < 0 {
+			return ProductPatch{}, ErrPriceIsInvalid
+		}
+	}
+
+	if req.Stock != nil && *req.Stock < 0 {
+		return ProductPatch{}, ErrStockIsInvalid
+	}
+
+	if req.CategoryId != nil && *req.CategoryId <= 0 {
+		return ProductPatch{}, ErrCategoryIdIsRequired
+	}
+
+	if req.ImageUrl != nil && *req.ImageUrl == "" {
+		return ProductPatch{}, ErrImageUrlIsRequired
+	}
+
+	if req.LowStockThreshold != nil && *req.LowStockThreshold < 0 {
+		return ProductPatch{}, ErrLowStockThresholdIsInvalid
+	}
+
+	return ProductPatch{
+		Name:              req.Name,
+		Description:       req.Description,
+		Price:             req.Price,
+		Stock:             req.Stock,
+		CategoryId:        req.CategoryId,
+		ImageUrl:          req.ImageUrl,
+		LowStockThreshold: req.LowStockThreshold,
+	}, nil
+}
+
+func (p Product) ApplyPatch(patch ProductPatch) Product {
+	if patch.Name != nil {
+		p.Name = *patch.Name
+	}
+
+	if patch.Description != nil {
+		p.Description = *patch.Description
+	}
+
+	if patch.Price != nil {
+		p.Price = *patch.Price
+	}
+
+	if patch.Stock != nil {
+		p.Stock = *patch.Stock
+	}
+
+	if patch.CategoryId != nil {
+		p.CategoryId = *patch.CategoryId
+	}
+
+	if patch.ImageUrl != nil {
+		p.ImageUrl = *patch.ImageUrl
+	}
+
+	if patch.LowStockThreshold != nil {
+		p.LowStockThreshold = *patch.LowStockThreshold
+	}
+
+	return p
+}
+
+func (p Product) ETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+func (p Product) MatchETag(header string, version int) bool {
+	etag := p.ETag(version)
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Product) ParseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil || version <= 0 || header != p.ETag(version) {
+		return 0, ErrVersionMismatch
+	}
+
+	return version, nil
+}
+
+func (p Product) CheckVersion(expected, current int) error {
+	if expected != 0 && expected != current {
+		return ErrVersionMismatch
+	}
+
+	return nil
 }
 
 func (p Product) ProductResponse(products []Product) []dto.GetListProductResponse {
@@ -121,17 +312,22 @@ func (p Product) ProductResponse(products []Product) []dto.GetListProductRespons
 
 func (p Product) ProductDetailResponse(product Product) dto.GetDetailProductResponse {
 	response := dto.GetDetailProductResponse{
-		ID:          product.ID,
-		Sku:         product.Sku,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		Stock:       product.Stock,
-		Category:    product.Category,
-		CategoryId:  product.CategoryId,
-		ImageUrl:    product.ImageUrl,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   p.NullStringScan(product.UpdatedAt),
+		ID:                product.ID,
+		Sku:               product.Sku,
+		Name:              product.Name,
+		Description:       product.Description,
+		Price:             product.Price,
+		Stock:             product.Stock,
+		Category:          product.Category,
+		CategoryId:        product.CategoryId,
+		ImageUrl:          product.ImageUrl,
+		LowStockThreshold: product.LowStockThreshold,
+		Images:            NewProductImage().GalleryResponse(product.Images),
+		Options:           p.OptionResponse(product.Options),
+		Variants:          p.VariantResponse(product, product.Variants),
+		Version:           product.Version,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         p.NullStringScan(product.UpdatedAt),
 	}
 
 	return response
@@ -153,6 +349,9 @@ func (p Product) ProductDetailUserPerspectiveResponse(product Product) dto.GetDe
 			City: product.MerchantCity,
 		},
 		ImageUrl:  product.ImageUrl,
+		Images:    NewProductImage().GalleryResponse(product.Images),
+		Options:   p.OptionResponse(product.Options),
+		Variants:  p.VariantResponse(product, product.Variants),
 		CreatedAt: product.CreatedAt,
 		UpdatedAt: p.NullStringScan(product.UpdatedAt),
 	}
@@ -160,6 +359,133 @@ func (p Product) ProductDetailUserPerspectiveResponse(product Product) dto.GetDe
 	return response
 }
 
+func (p Product) ValidateCatalogFilter(req dto.GetCatalogRequest) (CatalogFilter, error) {
+	filter := CatalogFilter{
+		Query:      req.Query,
+		CategoryId: req.CategoryId,
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		City:       req.City,
+		InStock:    req.InStock,
+		Sort:       req.Sort,
+		Limit:      req.Limit,
+		Page:       req.Page,
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = SortNewest
+	case SortNewest, SortPriceAsc, SortPriceDesc, SortNameAsc, SortNameDesc:
+	default:
+		return CatalogFilter{}, ErrSortIsInvalid
+	}
+
+	if filter.CategoryId < 0 || filter.MinPrice < 0 || filter.MaxPrice < 0 || filter.Limit < 0 || filter.Page < 0 {
+		return CatalogFilter{}, ErrInvalidQueryParam
+	}
+
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return CatalogFilter{}, ErrPriceRangeIsInvalid
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+
+	return filter, nil
+}
+
+func (p Product) ValidateSearchFilter(req dto.GetCatalogRequest) (CatalogFilter, error) {
+	if req.Query == "" {
+		return CatalogFilter{}, ErrSearchQueryIsRequired
+	}
+
+	req.Sort = ""
+	filter, err := p.ValidateCatalogFilter(req)
+	if err != nil {
+		return CatalogFilter{}, err
+	}
+	filter.Sort = SortRelevance
+
+	return filter, nil
+}
+
+func (p Product) SearchResponse(products []Product) []dto.SearchProductResponse {
+	responses := []dto.SearchProductResponse{}
+
+	for _, product := range products {
+		response := dto.SearchProductResponse{
+			ID:         product.ID,
+			Sku:        product.Sku,
+			Name:       product.Name,
+			Price:      product.Price,
+			Stock:      product.Stock,
+			Category:   product.Category,
+			CategoryId: product.CategoryId,
+			Merchant: dto.Merchant{
+				ID:   product.MerchantId,
+				Name: product.MerchantName,
+				City: product.MerchantCity,
+			},
+			ImageUrl:      product.ImageUrl,
+			NameHighlight: highlightSnippet(product.NameHighlight),
+			Snippet:       highlightSnippet(product.Snippet),
+			Rank:          product.Rank,
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
+
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+)
+
+var snippetReplacer = strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>")
+
+// the headline is merchant input, only the match markers become markup
+func highlightSnippet(snippet string) string {
+	return snippetReplacer.Replace(html.EscapeString(snippet))
+}
+
+func (p Product) CatalogResponse(products []Product) []dto.GetCatalogProductResponse {
+	responses := []dto.GetCatalogProductResponse{}
+
+	for _, product := range products {
+		response := dto.GetCatalogProductResponse{
+			ID:         product.ID,
+			Sku:        product.Sku,
+			Name:       product.Name,
+			Price:      product.Price,
+			Stock:      product.Stock,
+			Category:   product.Category,
+			CategoryId: product.CategoryId,
+			Merchant: dto.Merchant{
+				ID:   product.MerchantId,
+				Name: product.MerchantName,
+				City: product.MerchantCity,
+			},
+			ImageUrl:  product.ImageUrl,
+			CreatedAt: product.CreatedAt,
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
+
 func (p Product) NullStringScan(value *string) string {
 	if value == nil {
 		return ""
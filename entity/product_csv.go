@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ecommerce/dto"
+)
+
+var (
+	ErrImportFileIsRequired  = errors.New("import file is required")
+	ErrImportFileIsTooLarge  = errors.New("import file is too large")
+	ErrImportFileIsInvalid   = errors.New("import file is not a valid csv")
+	ErrImportHeaderIsInvalid = errors.New("import header must contain name, description, price, stock, category and image_url")
+	ErrImportTooManyRows     = errors.New("import file has too many rows")
+)
+
+const (
+	MaxImportRows     = 1000
+	MaxImportFileSize = 2 * 1024 * 1024
+)
+
+var ProductCSVHeader = []string{"sku", "name", "description", "price", "stock", "category", "image_url"}
+
+var requiredImportColumns = []string{"name", "description", "price", "stock", "category", "image_url"}
+
+type ProductImportRow struct {
+	Line     int
+	Category string
+	Request  dto.CreateOrUpdateProductRequest
+	Err      error
+}
+
+func (p Product) ParseImportCSV(r io.Reader) ([]ProductImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrImportHeaderIsInvalid
+	}
+	if err != nil {
+		return nil, ErrImportFileIsInvalid
+	}
+
+	// spreadsheet exports often start with a byte order mark
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, ErrImportHeaderIsInvalid
+		}
+	}
+
+	rows := []ProductImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrImportFileIsInvalid
+		}
+
+		if len(rows) == MaxImportRows {
+			return nil, ErrImportTooManyRows
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := func(name string) string {
+			if columns[name] >= len(record) {
+				return ""
+			}
+			return unescapeCSVFormula(strings.TrimSpace(record[columns[name]]))
+		}
+
+		row := ProductImportRow{
+			Line:     line,
+			Category: value("category"),
+			Request: dto.CreateOrUpdateProductRequest{
+				Name:        value("name"),
+				Description: value("description"),
+				ImageUrl:    value("image_url"),
+			},
+		}
+
+		if row.Request.Price, err = strconv.Atoi(value("price")); err != nil && value("price") != "" {
+			row.Err = ErrPriceIsInvalid
+		}
+
+		if row.Request.Stock, err = strconv.Atoi(value("stock")); err != nil && value("stock") != "" && row.Err == nil {
+			row.Err = ErrStockIsInvalid
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// CSVRecord renders a product in ProductCSVHeader order. Text cells that a
+// spreadsheet would evaluate as a formula are prefixed with a quote.
+func (p Product) CSVRecord(product Product) []string {
+	return []string{
+		escapeCSVFormula(product.Sku),
+		escapeCSVFormula(product.Name),
+		escapeCSVFormula(product.Description),
+		strconv.Itoa(product.Price),
+		strconv.Itoa(product.Stock),
+		escapeCSVFormula(product.Category),
+		escapeCSVFormula(product.ImageUrl),
+	}
+}
+
+func escapeCSVFormula(value string) string {
+	if isCSVFormula(value) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func unescapeCSVFormula(value string) string {
+	if strings.HasPrefix(value, "'") && isCSVFormula(value[1:]) {
+		return value[1:]
+	}
+
+	return value
+}
+
+func isCSVFormula(value string) bool {
+	return value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0]))
+}
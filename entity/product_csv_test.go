@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityProductCSV(t *testing.T) {
+	t.Run("err : header is missing a column", func(t *testing.T) {
+		_, err := NewProduct().ParseImportCSV(strings.NewReader("name,description,price,stock,image_url\n"))
+		require.Equal(t, ErrImportHeaderIsInvalid, err)
+	})
+
+	t.Run("err : file is empty", func(t *testing.T) {
+		_, err := NewProduct().ParseImportCSV(strings.NewReader(""))
+		require.Equal(t, ErrImportHeaderIsInvalid, err)
+	})
+
+	t.Run("err : file is not a valid csv", func(t *testing.T) {
+		_, err := NewProduct().ParseImportCSV(strings.NewReader("name,description,price,stock,category,image_url\n\"shirt,a,1,1,b,c\n"))
+		require.Equal(t, ErrImportFileIsInvalid, err)
+	})
+
+	t.Run("err : file has too many rows", func(t *testing.T) {
+		file := "name,description,price,stock,category,image_url\n" + strings.Repeat("shirt,a,1,1,b,c\n", MaxImportRows+1)
+
+		_, err := NewProduct().ParseImportCSV(strings.NewReader(file))
+		require.Equal(t, ErrImportTooManyRows, err)
+	})
+
+	t.Run("success : columns are matched by name", func(t *testing.T) {
+		file := "\ufeffImage_Url,Category,Stock,Price,Description,Name,sku\n" +
+			"shirt.png,Fashion,5,10000,cotton shirt,shirt,ignored\n" +
+			"mug.png,Kitchen,x,5000,ceramic mug,mug\n"
+
+		rows, err := NewProduct().ParseImportCSV(strings.NewReader(file))
+		require.Nil(t, err)
+		require.Len(t, rows, 2)
+
+		require.Equal(t, 2, rows[0].Line)
+		require.Equal(t, "Fashion", rows[0].Category)
+		require.Equal(t, "shirt", rows[0].Request.Name)
+		require.Equal(t, 10000, rows[0].Request.Price)
+		require.Equal(t, 5, rows[0].Request.Stock)
+		require.Equal(t, "shirt.png", rows[0].Request.ImageUrl)
+		require.Nil(t, rows[0].Err)
+
+		require.Equal(t, 3, rows[1].Line)
+		require.Equal(t, ErrStockIsInvalid, rows[1].Err)
+	})
+
+	t.Run("success : export escapes formulas", func(t *testing.T) {
+		record := NewProduct().CSVRecord(Product{
+			Sku:         "sku",
+			Name:        "=HYPERLINK(\"x\")",
+			Description: "@SUM(A1)",
+			Price:       1000,
+			Stock:       2,
+			Category:    "+Books",
+			ImageUrl:    "-image.png",
+		})
+
+		require.Equal(t, []string{"sku", "'=HYPERLINK(\"x\")", "'@SUM(A1)", "1000", "2", "'+Books", "'-image.png"}, record)
+	})
+
+	t.Run("success : exported file imports with the original values", func(t *testing.T) {
+		product := Product{
+			Sku:         "sku",
+			Name:        "-50% Sale",
+			Description: "=cmd, 'quoted' and @home",
+			Price:       1000,
+			Stock:       2,
+			Category:    "+Books",
+			ImageUrl:    "'image.png",
+		}
+
+		var buffer bytes.Buffer
+		writer := csv.NewWriter(&buffer)
+		require.Nil(t, writer.Write(ProductCSVHeader))
+		require.Nil(t, writer.Write(NewProduct().CSVRecord(product)))
+		writer.Flush()
+
+		rows, err := NewProduct().ParseImportCSV(&buffer)
+		require.Nil(t, err)
+		require.Len(t, rows, 1)
+
+		require.Equal(t, "-50% Sale", rows[0].Request.Name)
+		require.Equal(t, "=cmd, 'quoted' and @home", rows[0].Request.Description)
+		require.Equal(t, 1000, rows[0].Request.Price)
+		require.Equal(t, 2, rows[0].Request.Stock)
+		require.Equal(t, "+Books", rows[0].Category)
+		require.Equal(t, "'image.png", rows[0].Request.ImageUrl)
+	})
+}
@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/ecommerce/dto"
+)
+
+var (
+	ErrImageUrlIsInvalid    = errors.New("image url is invalid")
+	ErrAltTextIsInvalid     = errors.New("alt text is too long")
+	ErrTooManyImages        = errors.New("product image gallery is full")
+	ErrImageOrderIsInvalid  = errors.New("image order must list every image of the product once")
+	ErrProductImageNotFound = errors.New("product image not found")
+)
+
+const (
+	MaxProductImages = 10
+	MaxAltTextLength = 255
+)
+
+type ProductImage struct {
+	ID        int    `db:"id"`
+	ProductId int    `db:"product_id"`
+	ImageUrl  string `db:"image_url"`
+	AltText   string `db:"alt_text"`
+	Position  int    `db:"position"`
+	CreatedAt string `db:"created_at"`
+}
+
+func NewProductImage() ProductImage {
+	return ProductImage{}
+}
+
+func (i ProductImage) Validate(req dto.AttachProductImageRequest, productId int) (ProductImage, error) {
+	if req.ImageUrl == "" {
+		return i, ErrImageUrlIsRequired
+	}
+
+	parsed, err := url.Parse(req.ImageUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return i, ErrImageUrlIsInvalid
+	}
+
+	if len(req.AltText) > MaxAltTextLength {
+		return i, ErrAltTextIsInvalid
+	}
+
+	i.ProductId = productId
+	i.ImageUrl = req.ImageUrl
+	i.AltText = req.AltText
+
+	return i, nil
+}
+
+func (i ProductImage) ValidateOrder(ids []int, images []ProductImage) error {
+	if len(ids) != len(images) {
+		return ErrImageOrderIsInvalid
+	}
+
+	current := map[int]bool{}
+	for _, image := range images {
+		current[image.ID] = true
+	}
+
+	for _, id := range ids {
+		if !current[id] {
+			return ErrImageOrderIsInvalid
+		}
+		delete(current, id)
+	}
+
+	return nil
+}
+
+func (i ProductImage) ImageResponse(image ProductImage) dto.ProductImageResponse {
+	return dto.ProductImageResponse{
+		ID:       image.ID,
+		ImageUrl: image.ImageUrl,
+		AltText:  image.AltText,
+		Position: image.Position,
+	}
+}
+
+func (i ProductImage) GalleryResponse(images []ProductImage) []dto.ProductImageResponse {
+	responses := []dto.ProductImageResponse{}
+
+	for _, image := range images {
+		responses = append(responses, i.ImageResponse(image))
+	}
+
+	return responses
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ecommerce/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityProductImage(t *testing.T) {
+	t.Run("err : image url is required", func(t *testing.T) {
+		_, err := NewProductImage().Validate(dto.AttachProductImageRequest{}, 1)
+		require.Equal(t, ErrImageUrlIsRequired, err)
+	})
+
+	t.Run("err : image url is invalid", func(t *testing.T) {
+		for _, imageUrl := range []string{"image.png", "javascript:alert(1)", "ftp://cdn/image.png", "https://"} {
+			_, err := NewProductImage().Validate(dto.AttachProductImageRequest{ImageUrl: imageUrl}, 1)
+			require.Equal(t, ErrImageUrlIsInvalid, err, imageUrl)
+		}
+	})
+
+	t.Run("err : alt text is too long", func(t *testing.T) {
+		_, err := NewProductImage().Validate(dto.AttachProductImageRequest{
+			ImageUrl: "https://cdn/image.png",
+			AltText:  strings.Repeat("a", MaxAltTextLength+1),
+		}, 1)
+		require.Equal(t, ErrAltTextIsInvalid, err)
+	})
+
+	t.Run("success : validate image request", func(t *testing.T) {
+		image, err := NewProductImage().Validate(dto.AttachProductImageRequest{ImageUrl: "https://cdn/image.png", AltText: "front"}, 7)
+		require.Nil(t, err)
+		require.Equal(t, ProductImage{ProductId: 7, ImageUrl: "https://cdn/image.png", AltText: "front"}, image)
+	})
+
+	t.Run("err : order must list every image once", func(t *testing.T) {
+		images := []ProductImage{{ID: 1}, {ID: 2}, {ID: 3}}
+
+		require.Equal(t, ErrImageOrderIsInvalid, NewProductImage().ValidateOrder([]int{1, 2}, images))
+		require.Equal(t, ErrImageOrderIsInvalid, NewProductImage().ValidateOrder([]int{1, 2, 2}, images))
+		require.Equal(t, ErrImageOrderIsInvalid, NewProductImage().ValidateOrder([]int{1, 2, 4}, images))
+		require.Nil(t, NewProductImage().ValidateOrder([]int{3, 1, 2}, images))
+	})
+}
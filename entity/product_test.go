@@ -141,15 +141,206 @@ func TestEntityProduct(t *testing.T) {
 		_, err := NewProduct().Validate(req, "1")
 		require.Nil(t, err)
 	})
+}
+
+func TestEntityCatalogFilter(t *testing.T) {
+	t.Run("err : sort is invalid", func(t *testing.T) {
+		var req = dto.GetCatalogRequest{
+			Sort: "price; DROP TABLE products",
+		}
+
+		_, err := NewProduct().ValidateCatalogFilter(req)
+		require.NotNil(t, err)
+		require.Equal(t, ErrSortIsInvalid, err)
+	})
+
+	t.Run("err : negative value is invalid", func(t *testing.T) {
+		var req = dto.GetCatalogRequest{
+			MinPrice: -1,
+		}
+
+		_, err := NewProduct().ValidateCatalogFilter(req)
+		require.NotNil(t, err)
+		require.Equal(t, ErrInvalidQueryParam, err)
+	})
+
+	t.Run("err : price range is invalid", func(t *testing.T) {
+		var req = dto.GetCatalogRequest{
+			MinPrice: 5000,
+			MaxPrice: 1000,
+		}
+
+		_, err := NewProduct().ValidateCatalogFilter(req)
+		require.NotNil(t, err)
+		require.Equal(t, ErrPriceRangeIsInvalid, err)
+	})
+
+	t.Run("success : apply defaults", func(t *testing.T) {
+		filter, err := NewProduct().ValidateCatalogFilter(dto.GetCatalogRequest{})
+		require.Nil(t, err)
+		require.Equal(t, SortNewest, filter.Sort)
+		require.Equal(t, DefaultLimit, filter.Limit)
+		require.Equal(t, 1, filter.Page)
+	})
+
+	t.Run("success : cap limit", func(t *testing.T) {
+		var req = dto.GetCatalogRequest{
+			Sort:  SortPriceAsc,
+			Limit: 1000,
+			Page:  2,
+		}
 
-	t.Run("err : invalid role", func(t *testing.T) {
-		err := NewProduct().CheckUserRole("user")
+		filter, err := NewProduct().ValidateCatalogFilter(req)
+		require.Nil(t, err)
+		require.Equal(t, SortPriceAsc, filter.Sort)
+		require.Equal(t, MaxLimit, filter.Limit)
+		require.Equal(t, 2, filter.Page)
+	})
+}
+
+func TestEntitySearchFilter(t *testing.T) {
+	t.Run("err : query is required", func(t *testing.T) {
+		_, err := NewProduct().ValidateSearchFilter(dto.GetCatalogRequest{})
 		require.NotNil(t, err)
-		require.Equal(t, ErrInvalidRole, err)
+		require.Equal(t, ErrSearchQueryIsRequired, err)
 	})
 
-	t.Run("success : valid role", func(t *testing.T) {
-		err := NewProduct().CheckUserRole("merchant")
+	t.Run("err : price range is invalid", func(t *testing.T) {
+		var req = dto.GetCatalogRequest{
+			Query:    "kopi",
+			MinPrice: 5000,
+			MaxPrice: 1000,
+		}
+
+		_, err := NewProduct().ValidateSearchFilter(req)
+		require.NotNil(t, err)
+		require.Equal(t, ErrPriceRangeIsInvalid, err)
+	})
+
+	t.Run("success : always sort by relevance", func(t *testing.T) {
+		var req = dto.GetCatalogRequest{
+			Query: "kopi",
+			Sort:  SortPriceAsc,
+		}
+
+		filter, err := NewProduct().ValidateSearchFilter(req)
 		require.Nil(t, err)
+		require.Equal(t, SortRelevance, filter.Sort)
+		require.Equal(t, "kopi", filter.Query)
+	})
+
+	t.Run("success : snippet escapes the description", func(t *testing.T) {
+		responses := NewProduct().SearchResponse([]Product{{Snippet: "<img src=x onerror=alert(1)> \x02kopi\x03 & <mark>teh</mark>"}})
+		require.Equal(t, "&lt;img src=x onerror=alert(1)&gt; <mark>kopi</mark> &amp; &lt;mark&gt;teh&lt;/mark&gt;", responses[0].Snippet)
+	})
+
+	t.Run("success : name only match is highlighted in the name", func(t *testing.T) {
+		responses := NewProduct().SearchResponse([]Product{{
+			Name:          "Kopi <Arabika>",
+			NameHighlight: "\x02Kopi\x03 <Arabika>",
+			Snippet:       "biji pilihan dari gayo",
+		}})
+		require.Equal(t, "<mark>Kopi</mark> &lt;Arabika&gt;", responses[0].NameHighlight)
+		require.Equal(t, "biji pilihan dari gayo", responses[0].Snippet)
+		require.Equal(t, "Kopi <Arabika>", responses[0].Name)
+	})
+}
+
+func TestEntityProductPatch(t *testing.T) {
+	name := "new name"
+	empty := ""
+	price := 15000
+	zero := 0
+	negative := -1
+
+	t.Run("err : no fields to update", func(t *testing.T) {
+		_, err := NewProduct().ValidatePatch(dto.UpdateProductRequest{})
+		require.Equal(t, ErrNoFieldsToUpdate, err)
+	})
+
+	t.Run("err : product name is required", func(t *testing.T) {
+		_, err := NewProduct().ValidatePatch(dto.UpdateProductRequest{Name: &empty})
+		require.Equal(t, ErrProductNameIsRequired, err)
+	})
+
+	t.Run("err : product price is required", func(t *testing.T) {
+		_, err := NewProduct().ValidatePatch(dto.UpdateProductRequest{Price: &zero})
+		require.Equal(t, ErrPriceIsRequired, err)
+	})
+
+	t.Run("err : product price is invalid", func(t *testing.T) {
+		_, err := NewProduct().ValidatePatch(dto.UpdateProductRequest{Price: &negative})
+		require.Equal(t, ErrPriceIsInvalid, err)
+	})
+
+	t.Run("err : product stock is invalid", func(t *testing.T) {
+		_, err := NewProduct().ValidatePatch(dto.UpdateProductRequest{Stock: &negative})
+		require.Equal(t, ErrStockIsInvalid, err)
+	})
+
+	t.Run("success : stock can be patched to zero", func(t *testing.T) {
+		_, err := NewProduct().ValidatePatch(dto.UpdateProductRequest{Stock: &zero})
+		require.Nil(t, err)
+	})
+
+	t.Run("success : apply only patched fields", func(t *testing.T) {
+		patch, err := NewProduct().ValidatePatch(dto.UpdateProductRequest{Name: &name, Price: &price})
+		require.Nil(t, err)
+
+		product := Product{
+			ID:          1,
+			Sku:         "sku",
+			Name:        "old name",
+			Description: "description",
+			Price:       1000,
+			Stock:       10,
+		}.ApplyPatch(patch)
+
+		require.Equal(t, "new name", product.Name)
+		require.Equal(t, 15000, product.Price)
+		require.Equal(t, "description", product.Description)
+		require.Equal(t, 10, product.Stock)
+		require.Equal(t, "sku", product.Sku)
+	})
+}
+
+func TestEntityProductETag(t *testing.T) {
+	t.Run("success : etag is the quoted version", func(t *testing.T) {
+		require.Equal(t, `"3"`, NewProduct().ETag(3))
+	})
+
+	t.Run("success : match etag", func(t *testing.T) {
+		require.True(t, NewProduct().MatchETag(`"3"`, 3))
+		require.True(t, NewProduct().MatchETag(`W/"3"`, 3))
+		require.True(t, NewProduct().MatchETag(`"1", "3"`, 3))
+		require.True(t, NewProduct().MatchETag(`*`, 3))
+		require.False(t, NewProduct().MatchETag(`"2"`, 3))
+	})
+
+	t.Run("success : parse if match", func(t *testing.T) {
+		version, err := NewProduct().ParseIfMatch(`"3"`)
+		require.Nil(t, err)
+		require.Equal(t, 3, version)
+
+		version, err = NewProduct().ParseIfMatch("")
+		require.Nil(t, err)
+		require.Equal(t, 0, version)
+
+		version, err = NewProduct().ParseIfMatch("*")
+		require.Nil(t, err)
+		require.Equal(t, 0, version)
+	})
+
+	t.Run("err : unknown if match cannot match", func(t *testing.T) {
+		for _, header := range []string{`3`, `"abc"`, `"0"`, `W/"3"`, `"1", "3"`} {
+			_, err := NewProduct().ParseIfMatch(header)
+			require.Equal(t, ErrVersionMismatch, err, header)
+		}
+	})
+
+	t.Run("err : check version", func(t *testing.T) {
+		require.Nil(t, NewProduct().CheckVersion(0, 3))
+		require.Nil(t, NewProduct().CheckVersion(3, 3))
+		require.Equal(t, ErrVersionMismatch, NewProduct().CheckVersion(2, 3))
 	})
 }
@@ -0,0 +1,227 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ecommerce/dto"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOptionNameIsRequired    = errors.New("option name is required")
+	ErrOptionValuesIsRequired  = errors.New("option values is required")
+	ErrOptionIsDuplicate       = errors.New("option name is duplicate")
+	ErrVariantsIsRequired      = errors.New("variants is required when options are set")
+	ErrVariantOptionsIsInvalid = errors.New("variant options must pick one value of every option")
+	ErrVariantIsDuplicate      = errors.New("variant options combination is duplicate")
+	ErrVariantPriceIsInvalid   = errors.New("variant price is invalid")
+	ErrVariantStockIsInvalid   = errors.New("variant stock is invalid")
+	ErrStockManagedByVariants  = errors.New("stock is managed per variant for this product")
+)
+
+type VariantOptions map[string]string
+
+func (v *VariantOptions) Scan(src interface{}) error {
+	return scanJSON(src, v)
+}
+
+func (v VariantOptions) Value() (driver.Value, error) {
+	return json.Marshal(v)
+}
+
+func (v VariantOptions) Key() string {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, v[name]))
+	}
+
+	return strings.Join(pairs, ";")
+}
+
+type OptionValues []string
+
+func (o *OptionValues) Scan(src interface{}) error {
+	return scanJSON(src, o)
+}
+
+func (o OptionValues) Value() (driver.Value, error) {
+	return json.Marshal(o)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, dest)
+	case string:
+		return json.Unmarshal([]byte(value), dest)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+}
+
+type ProductOption struct {
+	ID        int          `db:"id"`
+	ProductId int          `db:"product_id"`
+	Name      string       `db:"name"`
+	Values    OptionValues `db:"option_values"`
+	Position  int          `db:"position"`
+}
+
+type ProductVariant struct {
+	ID        int            `db:"id"`
+	ProductId int            `db:"product_id"`
+	Sku       string         `db:"sku"`
+	Options   VariantOptions `db:"options"`
+	Price     *int           `db:"price"`
+	Stock     int            `db:"stock"`
+	ImageUrl  *string        `db:"image_url"`
+}
+
+func (p Product) ValidateVariants(reqOptions []dto.ProductOptionRequest, reqVariants []dto.ProductVariantRequest) ([]ProductOption, []ProductVariant, error) {
+	options := []ProductOption{}
+	allowed := map[string]map[string]bool{}
+
+	for i, reqOption := range reqOptions {
+		name := strings.TrimSpace(reqOption.Name)
+		if name == "" {
+			return nil, nil, ErrOptionNameIsRequired
+		}
+
+		if _, ok := allowed[name]; ok {
+			return nil, nil, ErrOptionIsDuplicate
+		}
+
+		values := OptionValues{}
+		allowed[name] = map[string]bool{}
+		for _, value := range reqOption.Values {
+			value = strings.TrimSpace(value)
+			if value == "" || allowed[name][value] {
+				continue
+			}
+			allowed[name][value] = true
+			values = append(values, value)
+		}
+
+		if len(values) == 0 {
+			return nil, nil, ErrOptionValuesIsRequired
+		}
+
+		options = append(options, ProductOption{
+			Name:     name,
+			Values:   values,
+			Position: i,
+		})
+	}
+
+	if len(options) > 0 && len(reqVariants) == 0 {
+		return nil, nil, ErrVariantsIsRequired
+	}
+
+	variants := []ProductVariant{}
+	seen := map[string]bool{}
+
+	for _, reqVariant := range reqVariants {
+		if len(reqVariant.Options) != len(options) || len(options) == 0 {
+			return nil, nil, ErrVariantOptionsIsInvalid
+		}
+
+		variantOptions := VariantOptions{}
+		for name, value := range reqVariant.Options {
+			if !allowed[name][value] {
+				return nil, nil, ErrVariantOptionsIsInvalid
+			}
+			variantOptions[name] = value
+		}
+
+		if seen[variantOptions.Key()] {
+			return nil, nil, ErrVariantIsDuplicate
+		}
+		seen[variantOptions.Key()] = true
+
+		if reqVariant.Price != nil && *reqVariant.Price <= 0 {
+			return nil, nil, ErrVariantPriceIsInvalid
+		}
+
+		if reqVariant.Stock < 0 {
+			return nil, nil, ErrVariantStockIsInvalid
+		}
+
+		variant := ProductVariant{
+			Sku:     uuid.New().String(),
+			Options: variantOptions,
+			Price:   reqVariant.Price,
+			Stock:   reqVariant.Stock,
+		}
+
+		if reqVariant.ImageUrl != "" {
+			imageUrl := reqVariant.ImageUrl
+			variant.ImageUrl = &imageUrl
+		}
+
+		variants = append(variants, variant)
+	}
+
+	return options, variants, nil
+}
+
+func (p Product) VariantStock(variants []ProductVariant) int {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Stock
+	}
+
+	return total
+}
+
+func (p Product) OptionResponse(options []ProductOption) []dto.ProductOptionResponse {
+	responses := []dto.ProductOptionResponse{}
+
+	for _, option := range options {
+		responses = append(responses, dto.ProductOptionResponse{
+			Name:   option.Name,
+			Values: []string(option.Values),
+		})
+	}
+
+	return responses
+}
+
+func (p Product) VariantResponse(product Product, variants []ProductVariant) []dto.ProductVariantResponse {
+	responses := []dto.ProductVariantResponse{}
+
+	for _, variant := range variants {
+		response := dto.ProductVariantResponse{
+			ID:       variant.ID,
+			Sku:      variant.Sku,
+			Options:  map[string]string(variant.Options),
+			Price:    product.Price,
+			Stock:    variant.Stock,
+			ImageUrl: product.ImageUrl,
+		}
+
+		if variant.Price != nil {
+			response.Price = *variant.Price
+		}
+
+		if variant.ImageUrl != nil {
+			response.ImageUrl = *variant.ImageUrl
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
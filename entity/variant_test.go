@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/ecommerce/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityProductVariant(t *testing.T) {
+	price := 12000
+	zero := 0
+
+	newRequest := func(options []dto.ProductOptionRequest, variants []dto.ProductVariantRequest) dto.CreateOrUpdateProductRequest {
+		return dto.CreateOrUpdateProductRequest{
+			Name:        "test",
+			Description: "test",
+			Price:       1000,
+			CategoryId:  1,
+			ImageUrl:    "test",
+			Options:     options,
+			Variants:    variants,
+		}
+	}
+
+	options := []dto.ProductOptionRequest{
+		{Name: "size", Values: []string{"S", "M"}},
+		{Name: "color", Values: []string{"red"}},
+	}
+
+	t.Run("err : option name is required", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest([]dto.ProductOptionRequest{{Name: " ", Values: []string{"S"}}}, nil), "1")
+		require.Equal(t, ErrOptionNameIsRequired, err)
+	})
+
+	t.Run("err : option values is required", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest([]dto.ProductOptionRequest{{Name: "size", Values: []string{""}}}, nil), "1")
+		require.Equal(t, ErrOptionValuesIsRequired, err)
+	})
+
+	t.Run("err : option is duplicate", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest([]dto.ProductOptionRequest{
+			{Name: "size", Values: []string{"S"}},
+			{Name: "size", Values: []string{"M"}},
+		}, nil), "1")
+		require.Equal(t, ErrOptionIsDuplicate, err)
+	})
+
+	t.Run("err : options without variants", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest(options, nil), "1")
+		require.Equal(t, ErrVariantsIsRequired, err)
+	})
+
+	t.Run("err : variants without options", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest(nil, []dto.ProductVariantRequest{{Stock: 1}}), "1")
+		require.Equal(t, ErrVariantOptionsIsInvalid, err)
+	})
+
+	t.Run("err : variant misses an option", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest(options, []dto.ProductVariantRequest{
+			{Options: map[string]string{"size": "S"}, Stock: 1},
+		}), "1")
+		require.Equal(t, ErrVariantOptionsIsInvalid, err)
+	})
+
+	t.Run("err : variant uses an unknown value", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest(options, []dto.ProductVariantRequest{
+			{Options: map[string]string{"size": "XL", "color": "red"}, Stock: 1},
+		}), "1")
+		require.Equal(t, ErrVariantOptionsIsInvalid, err)
+	})
+
+	t.Run("err : variant is duplicate", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest(options, []dto.ProductVariantRequest{
+			{Options: map[string]string{"size": "S", "color": "red"}, Stock: 1},
+			{Options: map[string]string{"color": "red", "size": "S"}, Stock: 2},
+		}), "1")
+		require.Equal(t, ErrVariantIsDuplicate, err)
+	})
+
+	t.Run("err : variant price is invalid", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest(options, []dto.ProductVariantRequest{
+			{Options: map[string]string{"size": "S", "color": "red"}, Price: &zero, Stock: 1},
+		}), "1")
+		require.Equal(t, ErrVariantPriceIsInvalid, err)
+	})
+
+	t.Run("err : variant stock is invalid", func(t *testing.T) {
+		_, err := NewProduct().Validate(newRequest(options, []dto.ProductVariantRequest{
+			{Options: map[string]string{"size": "S", "color": "red"}, Stock: -1},
+		}), "1")
+		require.Equal(t, ErrVariantStockIsInvalid, err)
+	})
+
+	t.Run("success : stock is the sum of the variants", func(t *testing.T) {
+		product, err := NewProduct().Validate(newRequest(options, []dto.ProductVariantRequest{
+			{Options: map[string]string{"size": "S", "color": "red"}, Price: &price, Stock: 3},
+			{Options: map[string]string{"size": "M", "color": "red"}, Stock: 0, ImageUrl: "m.png"},
+		}), "1")
+		require.Nil(t, err)
+		require.True(t, product.ReplaceVariants)
+		require.Equal(t, 3, product.Stock)
+		require.Len(t, product.Options, 2)
+		require.Len(t, product.Variants, 2)
+		require.NotEmpty(t, product.Variants[0].Sku)
+		require.NotEqual(t, product.Variants[0].Sku, product.Variants[1].Sku)
+	})
+
+	t.Run("success : request without variants keeps them", func(t *testing.T) {
+		req := newRequest(nil, nil)
+		req.Stock = 10
+
+		product, err := NewProduct().Validate(req, "1")
+		require.Nil(t, err)
+		require.False(t, product.ReplaceVariants)
+		require.Equal(t, 10, product.Stock)
+	})
+
+	t.Run("success : variant response falls back to the product", func(t *testing.T) {
+		imageUrl := "m.png"
+		product := Product{Price: 1000, ImageUrl: "product.png"}
+
+		responses := NewProduct().VariantResponse(product, []ProductVariant{
+			{ID: 1, Sku: "s", Options: VariantOptions{"size": "S"}, Price: &price, Stock: 3},
+			{ID: 2, Sku: "m", Options: VariantOptions{"size": "M"}, ImageUrl: &imageUrl},
+		})
+
+		require.Equal(t, []dto.ProductVariantResponse{
+			{ID: 1, Sku: "s", Options: map[string]string{"size": "S"}, Price: 12000, Stock: 3, ImageUrl: "product.png"},
+			{ID: 2, Sku: "m", Options: map[string]string{"size": "M"}, Price: 1000, Stock: 0, ImageUrl: "m.png"},
+		}, responses)
+	})
+
+	t.Run("success : scan variant options", func(t *testing.T) {
+		var options VariantOptions
+		require.Nil(t, options.Scan([]byte(`{"size":"M","color":"red"}`)))
+		require.Equal(t, "color=red;size=M", options.Key())
+	})
+}
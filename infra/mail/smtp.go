@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/ecommerce/config"
+)
+
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) (err error)
+}
+
+type SMTP struct {
+	cfg config.Mail
+}
+
+func NewSMTP(cfg config.Mail) SMTP {
+	return SMTP{
+		cfg: cfg,
+	}
+}
+
+func (s SMTP) Send(ctx context.Context, to, subject, body string) (err error) {
+	addr := fmt.Sprintf("%s:%s", s.cfg.Host, s.cfg.Port)
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	message := strings.Join([]string{
+		fmt.Sprintf("From: %s", s.cfg.From),
+		fmt.Sprintf("To: %s", to),
+		fmt.Sprintf("Subject: %s", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	err = smtp.SendMail(addr, auth, s.cfg.From, []string{to}, []byte(message))
+	if err != nil {
+		return
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ecommerce/config"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts a single message, the same way MailHog would locally.
+func fakeSMTPServer(t *testing.T) (host, port string, received chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	received = make(chan string, 1)
+
+	go func() {
+		defer listener.Close()
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) {
+			conn.Write([]byte(line + "\r\n"))
+		}
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "DATA"):
+				reply("354 end data with <CR><LF>.<CR><LF>")
+
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				received <- data.String()
+				reply("250 ok")
+			case strings.HasPrefix(command, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	return host, port, received
+}
+
+func TestSMTPSend(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+
+	sender := NewSMTP(config.Mail{
+		Host: host,
+		Port: port,
+		From: "no-reply@ecommerce.local",
+	})
+
+	err := sender.Send(context.Background(), "user@gmail.com", "subject", "body")
+	require.NoError(t, err)
+
+	message := <-received
+	require.Contains(t, message, "To: user@gmail.com")
+	require.Contains(t, message, "Subject: subject")
+	require.Contains(t, message, "body")
+}
@@ -3,8 +3,10 @@ package middleware
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 const (
@@ -13,22 +15,56 @@ const (
 
 var (
 	ErrUnAuthorized = errors.New("please provide jwt token")
+	ErrTokenRevoked = errors.New("token has been revoked")
+	ErrTokenExpiry  = errors.New("token expiry is required")
+	ErrForbidden    = errors.New("you do not have access to this resource")
 )
 
 type Claims struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	// iat only has second precision, revocation compares this one
+	IssuedAtMilli int64 `json:"iat_ms,omitempty"`
 	jwt.RegisteredClaims
 }
 
-var jwtSecretKey = ""
+var (
+	jwtSecretKey = ""
+	jwtIssuer    = ""
+	jwtAudience  = ""
+)
 
 func SetJWTSecretKey(key string) {
 	jwtSecretKey = key
 }
 
+func SetJWTIssuer(issuer, audience string) {
+	jwtIssuer = issuer
+	jwtAudience = audience
+}
+
 func GenerateNewJWT(claims *Claims) (signedToken string, err error) {
+	if claims.ExpiresAt == nil {
+		return "", ErrTokenExpiry
+	}
+
+	if claims.RegisteredClaims.ID == "" {
+		claims.RegisteredClaims.ID = uuid.New().String()
+	}
+
+	if jwtIssuer != "" {
+		claims.Issuer = jwtIssuer
+	}
+
+	if jwtAudience != "" {
+		claims.Audience = jwt.ClaimStrings{jwtAudience}
+	}
+	now := time.Now()
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.IssuedAtMilli = now.UnixMilli()
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, *claims)
 	signedToken, err = token.SignedString([]byte(jwtSecretKey))
 	if err != nil {
@@ -44,10 +80,24 @@ func GetJWTClaims(tokenString string) (claims *Claims, err error) {
 	}
 	reqToken := strings.TrimSpace(splitToken[1])
 
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+
+	if jwtIssuer != "" {
+		options = append(options, jwt.WithIssuer(jwtIssuer))
+	}
+
+	if jwtAudience != "" {
+		options = append(options, jwt.WithAudience(jwtAudience))
+	}
+
 	claims = &Claims{}
 	token, err := jwt.ParseWithClaims(reqToken, claims, func(jwtToken *jwt.Token) (interface{}, error) {
 		return []byte(jwtSecretKey), nil
-	})
+	}, options...)
 	if err != nil {
 		return nil, ErrUnAuthorized
 	}
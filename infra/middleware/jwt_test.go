@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"fmt"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/ecommerce/config"
 	"github.com/gofiber/fiber/v2"
@@ -28,6 +30,9 @@ func TestGenerateNewJWT(t *testing.T) {
 		ID:    "1",
 		Email: "user@gmail.com",
 		Role:  "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
 	})
 	signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 
@@ -44,6 +49,9 @@ func TestGetJwtClaims(t *testing.T) {
 		ID:    "1",
 		Email: "user@gmail.com",
 		Role:  "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
 	})
 	signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
 	require.NoError(t, err)
@@ -60,3 +68,129 @@ func TestGetJwtClaims(t *testing.T) {
 	require.NotEmpty(t, claims.ID)
 	require.NotEqual(t, "", claims.ID)
 }
+
+func TestGenerateNewJWTRegisteredClaims(t *testing.T) {
+	SetJWTIssuer("issuer", "audience")
+	defer SetJWTIssuer("", "")
+
+	signedToken, err := GenerateNewJWT(&Claims{
+		ID:    "1",
+		Email: "user@gmail.com",
+		Role:  "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	require.NoError(t, err)
+
+	claims, err := GetJWTClaims("Bearer " + signedToken)
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.RegisteredClaims.ID)
+	require.NotNil(t, claims.IssuedAt)
+	require.Equal(t, "issuer", claims.Issuer)
+	require.Equal(t, jwt.ClaimStrings{"audience"}, claims.Audience)
+
+	SetJWTIssuer("other-issuer", "audience")
+	_, err = GetJWTClaims("Bearer " + signedToken)
+	require.Equal(t, ErrUnAuthorized, err)
+}
+
+func TestGenerateNewJWTIssuedAtMilli(t *testing.T) {
+	before := time.Now().UnixMilli()
+
+	signedToken, err := GenerateNewJWT(&Claims{
+		ID: "1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	require.NoError(t, err)
+
+	claims, err := GetJWTClaims("Bearer " + signedToken)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, claims.IssuedAtMilli, before)
+	require.Equal(t, claims.IssuedAt.Unix(), claims.IssuedAtMilli/1000)
+}
+
+func TestGenerateNewJWTExpiryRequired(t *testing.T) {
+	_, err := GenerateNewJWT(&Claims{
+		ID:    "1",
+		Email: "user@gmail.com",
+		Role:  "user",
+	})
+	require.Equal(t, ErrTokenExpiry, err)
+}
+
+func TestGetJwtClaimsRejectsTokens(t *testing.T) {
+	t.Run("err : token without expiry", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+			ID:    "1",
+			Email: "user@gmail.com",
+			Role:  "user",
+		})
+		signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+		require.NoError(t, err)
+
+		_, err = GetJWTClaims("Bearer " + signedToken)
+		require.Equal(t, ErrUnAuthorized, err)
+	})
+
+	t.Run("err : expired token", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+			ID:    "1",
+			Email: "user@gmail.com",
+			Role:  "user",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+			},
+		})
+		signedToken, err := token.SignedString([]byte(jwtSecret.Secret))
+		require.NoError(t, err)
+
+		_, err = GetJWTClaims("Bearer " + signedToken)
+		require.Equal(t, ErrUnAuthorized, err)
+	})
+}
+
+func TestRequireRole(t *testing.T) {
+	type testCase struct {
+		title              string
+		role               string
+		expectedStatusCode int
+	}
+
+	var testCases = []testCase{
+		{
+			title:              "allowed role",
+			role:               "merchant",
+			expectedStatusCode: fiber.StatusOK,
+		},
+		{
+			title:              "second allowed role",
+			role:               "admin",
+			expectedStatusCode: fiber.StatusOK,
+		},
+		{
+			title:              "forbidden role",
+			role:               "user",
+			expectedStatusCode: fiber.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			router := fiber.New()
+
+			router.Get("/", func(c *fiber.Ctx) error {
+				c.Locals("role", test.role)
+				return c.Next()
+			}, RequireRole("merchant", "admin"), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := router.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), 1)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
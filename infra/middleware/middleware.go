@@ -21,6 +21,11 @@ func AuthMiddleware() fiber.Handler {
 			return WriteError(ctx, err)
 		}
 
+		if err := CheckRevocation(ctx.UserContext(), claims); err != nil {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s", err.Error()))
+			return WriteError(ctx, err)
+		}
+
 		id := claims.ID
 		email := claims.Email
 		role := claims.Role
@@ -28,6 +33,9 @@ func AuthMiddleware() fiber.Handler {
 		ctx.Locals("id", id)
 		ctx.Locals("email", email)
 		ctx.Locals("role", role)
+		ctx.Locals("session_id", claims.SessionID)
+		ctx.Locals("jti", claims.RegisteredClaims.ID)
+		ctx.Locals("expires_at", claims.ExpiresAt.Time)
 
 		return ctx.Next()
 	}
@@ -10,8 +10,10 @@ func WriteError(c *fiber.Ctx, err error) error {
 	switch {
 	case err == ErrUnAuthorized:
 		return write(c, http.StatusUnauthorized, "unauthorized", err.Error(), "40001", nil)
-	case err == ErrUnAuthorized:
+	case err == ErrTokenRevoked:
 		return write(c, http.StatusUnauthorized, "unauthorized", err.Error(), "40002", nil)
+	case err == ErrForbidden:
+		return write(c, http.StatusForbidden, "forbidden", err.Error(), "40301", nil)
 	default:
 		return write(c, http.StatusInternalServerError, "internal server error", "unknown error", "99999", nil)
 	}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fmt"
+
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole only lets requests through when the role set by AuthMiddleware
+// is one of roles, so it must be registered after AuthMiddleware.
+func RequireRole(roles ...string) fiber.Handler {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		role, _ := ctx.Locals("role").(string)
+		if !allowed[role] {
+			logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelError, fmt.Sprintf("Error : %s, role : %s", ErrForbidden, role))
+			return WriteError(ctx, ErrForbidden)
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	logs "github.com/ecommerce/infra/logger"
+	"github.com/ecommerce/infra/mail"
+)
+
+const (
+	KindLog  = "log"
+	KindMail = "mail"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, recipient, subject, message string) (err error)
+}
+
+func New(kind string, sender mail.Sender) Notifier {
+	if kind == KindMail {
+		return NewMail(sender)
+	}
+
+	return NewLog()
+}
+
+type Log struct{}
+
+func NewLog() Log {
+	return Log{}
+}
+
+func (Log) Notify(ctx context.Context, recipient, subject, message string) (err error) {
+	logs.Logger(logs.GetFunctionPath(), logs.LoggerLevelInfo, fmt.Sprintf("notify %s : %s : %s", recipient, subject, message))
+	return
+}
+
+type Mail struct {
+	sender mail.Sender
+}
+
+func NewMail(sender mail.Sender) Mail {
+	return Mail{
+		sender: sender,
+	}
+}
+
+func (m Mail) Notify(ctx context.Context, recipient, subject, message string) (err error) {
+	return m.sender.Send(ctx, recipient, subject, message)
+}
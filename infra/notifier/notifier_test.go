@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockSender struct {
+	sent []string
+}
+
+// Send implements mail.Sender.
+func (m *mockSender) Send(ctx context.Context, to, subject, body string) (err error) {
+	m.sent = append(m.sent, to, subject, body)
+	return nil
+}
+
+func TestNotifier(t *testing.T) {
+	t.Run("success : mail notifier sends through the sender", func(t *testing.T) {
+		sender := &mockSender{}
+
+		err := New(KindMail, sender).Notify(context.Background(), "merchant@gmail.com", "Low stock", "1 left")
+		require.NoError(t, err)
+		require.Equal(t, []string{"merchant@gmail.com", "Low stock", "1 left"}, sender.sent)
+	})
+
+	t.Run("success : unknown kind falls back to log", func(t *testing.T) {
+		sender := &mockSender{}
+
+		notifier := New("", sender)
+		require.Equal(t, NewLog(), notifier)
+		require.NoError(t, notifier.Notify(context.Background(), "merchant@gmail.com", "Low stock", "1 left"))
+		require.Empty(t, sender.sent)
+	})
+}
@@ -0,0 +1,152 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/config"
+)
+
+const (
+	defaultInvoiceDuration = time.Hour
+	defaultTimeout         = 10 * time.Second
+)
+
+type HTTPGateway struct {
+	cfg      config.Payment
+	client   *http.Client
+	duration time.Duration
+}
+
+func NewHTTPGateway(cfg config.Payment) HTTPGateway {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	duration := time.Duration(cfg.InvoiceDurationMinute) * time.Minute
+	if duration <= 0 {
+		duration = defaultInvoiceDuration
+	}
+
+	return HTTPGateway{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: timeout},
+		duration: duration,
+	}
+}
+
+type invoiceRequest struct {
+	ExternalId      string `json:"external_id"`
+	Amount          int    `json:"amount"`
+	PayerEmail      string `json:"payer_email,omitempty"`
+	Description     string `json:"description"`
+	InvoiceDuration int    `json:"invoice_duration"`
+}
+
+type invoiceResponse struct {
+	Id         string    `json:"id"`
+	InvoiceUrl string    `json:"invoice_url"`
+	Status     string    `json:"status"`
+	ExpiryDate time.Time `json:"expiry_date"`
+}
+
+func (h HTTPGateway) CreateInvoice(ctx context.Context, invoice Invoice) (result InvoiceResult, err error) {
+	body, err := json.Marshal(invoiceRequest{
+		ExternalId:      invoice.ExternalId,
+		Amount:          invoice.Amount,
+		PayerEmail:      invoice.PayerEmail,
+		Description:     invoice.Description,
+		InvoiceDuration: int(h.duration.Seconds()),
+	})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(h.cfg.BaseURL, "/")+"/v2/invoices", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(h.cfg.SecretKey, "")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("create invoice failed with status %d", resp.StatusCode)
+	}
+
+	var response invoiceResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return
+	}
+
+	if response.Id == "" || response.InvoiceUrl == "" {
+		return result, fmt.Errorf("create invoice returned no invoice")
+	}
+
+	return InvoiceResult{
+		Id:         response.Id,
+		InvoiceUrl: response.InvoiceUrl,
+		Status:     response.Status,
+		ExpiresAt:  response.ExpiryDate,
+	}, nil
+}
+
+func (h HTTPGateway) ExpireInvoice(ctx context.Context, id string) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(h.cfg.BaseURL, "/")+"/invoices/"+url.PathEscape(id)+"/expire!", nil)
+	if err != nil {
+		return
+	}
+	req.SetBasicAuth(h.cfg.SecretKey, "")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("expire invoice failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (h HTTPGateway) VerifyCallback(signature string, body []byte) (callback Callback, err error) {
+	expected, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil || h.cfg.CallbackSecret == "" {
+		return callback, ErrSignatureIsInvalid
+	}
+
+	if !hmac.Equal(expected, Sign(h.cfg.CallbackSecret, body)) {
+		return callback, ErrSignatureIsInvalid
+	}
+
+	err = json.Unmarshal(body, &callback)
+	if err != nil || callback.ExternalId == "" {
+		return Callback{}, ErrCallbackIsInvalid
+	}
+
+	return callback, nil
+}
+
+func Sign(secret string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,140 @@
+package payment
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/config"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGateway serves the invoice API in process, the same way the sandbox of
+// the real gateway would.
+type fakeGateway struct {
+	mu       sync.Mutex
+	invoices map[string]invoiceRequest
+	expired  map[string]bool
+}
+
+func newFakeGateway(t *testing.T, secretKey string) (*fakeGateway, *httptest.Server) {
+	fake := &fakeGateway{invoices: map[string]invoiceRequest{}, expired: map[string]bool{}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		if !ok || user != secretKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/invoices/"), "/expire!"); ok && r.Method == http.MethodPost {
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+
+			if _, ok := fake.invoices[id]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			fake.expired[id] = true
+			json.NewEncoder(w).Encode(invoiceResponse{Id: id, Status: StatusExpired})
+			return
+		}
+
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/invoices" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var req invoiceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fake.mu.Lock()
+		id := fmt.Sprintf("inv-%d", len(fake.invoices)+1)
+		fake.invoices[id] = req
+		fake.mu.Unlock()
+
+		json.NewEncoder(w).Encode(invoiceResponse{
+			Id:         id,
+			InvoiceUrl: fmt.Sprintf("https://checkout.example.com/%s", id),
+			Status:     StatusPending,
+			ExpiryDate: time.Now().Add(time.Duration(req.InvoiceDuration) * time.Second).UTC(),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+func TestHTTPGateway(t *testing.T) {
+	fake, server := newFakeGateway(t, "secret-key")
+	cfg := config.Payment{BaseURL: server.URL, SecretKey: "secret-key", CallbackSecret: "callback-secret", InvoiceDurationMinute: 30}
+
+	t.Run("success : create invoice", func(t *testing.T) {
+		result, err := NewHTTPGateway(cfg).CreateInvoice(context.Background(), Invoice{ExternalId: "order-1", Amount: 25000, Description: "Order 1"})
+		require.NoError(t, err)
+		require.Equal(t, "inv-1", result.Id)
+		require.Equal(t, "https://checkout.example.com/inv-1", result.InvoiceUrl)
+		require.Equal(t, StatusPending, result.Status)
+		require.Equal(t, "order-1", fake.invoices["inv-1"].ExternalId)
+		require.Equal(t, 1800, fake.invoices["inv-1"].InvoiceDuration)
+	})
+
+	t.Run("err : gateway rejects the key", func(t *testing.T) {
+		wrong := cfg
+		wrong.SecretKey = "wrong"
+
+		_, err := NewHTTPGateway(wrong).CreateInvoice(context.Background(), Invoice{ExternalId: "order-2", Amount: 1000})
+		require.Error(t, err)
+	})
+
+	t.Run("success : expire invoice", func(t *testing.T) {
+		err := NewHTTPGateway(cfg).ExpireInvoice(context.Background(), "inv-1")
+		require.NoError(t, err)
+		require.True(t, fake.expired["inv-1"])
+	})
+
+	t.Run("err : expire unknown invoice", func(t *testing.T) {
+		err := NewHTTPGateway(cfg).ExpireInvoice(context.Background(), "inv-404")
+		require.Error(t, err)
+	})
+
+	t.Run("success : verify signed callback", func(t *testing.T) {
+		body := []byte(`{"id":"inv-1","external_id":"order-1","status":"PAID","amount":25000}`)
+		signature := hex.EncodeToString(Sign("callback-secret", body))
+
+		callback, err := NewHTTPGateway(cfg).VerifyCallback(signature, body)
+		require.NoError(t, err)
+		require.Equal(t, "order-1", callback.ExternalId)
+		require.Equal(t, 25000, callback.Amount)
+		require.True(t, callback.IsPaid())
+	})
+
+	t.Run("err : signature does not match", func(t *testing.T) {
+		body := []byte(`{"id":"inv-1","external_id":"order-1","status":"PAID","amount":25000}`)
+		signature := hex.EncodeToString(Sign("other-secret", body))
+
+		_, err := NewHTTPGateway(cfg).VerifyCallback(signature, body)
+		require.Equal(t, ErrSignatureIsInvalid, err)
+
+		_, err = NewHTTPGateway(cfg).VerifyCallback("not-hex", body)
+		require.Equal(t, ErrSignatureIsInvalid, err)
+	})
+
+	t.Run("err : signed body is not a callback", func(t *testing.T) {
+		body := []byte(`{"status":"PAID"}`)
+
+		_, err := NewHTTPGateway(cfg).VerifyCallback(hex.EncodeToString(Sign("callback-secret", body)), body)
+		require.Equal(t, ErrCallbackIsInvalid, err)
+	})
+}
@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSignatureIsInvalid = errors.New("callback signature is invalid")
+	ErrCallbackIsInvalid  = errors.New("callback body is invalid")
+)
+
+const SignatureHeader = "X-Callback-Signature"
+
+const (
+	StatusPending = "PENDING"
+	StatusPaid    = "PAID"
+	StatusSettled = "SETTLED"
+	StatusExpired = "EXPIRED"
+)
+
+type Invoice struct {
+	ExternalId  string
+	Amount      int
+	PayerEmail  string
+	Description string
+}
+
+type InvoiceResult struct {
+	Id         string
+	InvoiceUrl string
+	Status     string
+	ExpiresAt  time.Time
+}
+
+type Callback struct {
+	Id         string `json:"id"`
+	ExternalId string `json:"external_id"`
+	Status     string `json:"status"`
+	Amount     int    `json:"amount"`
+	PaidAt     string `json:"paid_at"`
+}
+
+func (c Callback) IsPaid() bool {
+	return c.Status == StatusPaid || c.Status == StatusSettled
+}
+
+type PaymentGateway interface {
+	CreateInvoice(ctx context.Context, invoice Invoice) (result InvoiceResult, err error)
+	ExpireInvoice(ctx context.Context, id string) (err error)
+	VerifyCallback(signature string, body []byte) (callback Callback, err error)
+}
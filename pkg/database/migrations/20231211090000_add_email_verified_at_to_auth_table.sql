@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "auth" ADD COLUMN IF NOT EXISTS "email_verified_at" TIMESTAMP NULL;
+
+-- accounts created before verification existed keep working
+UPDATE "auth" SET "email_verified_at" = "created_at" WHERE "email_verified_at" IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "auth" DROP COLUMN IF EXISTS "email_verified_at";
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'admin';
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE "auth" SET "role" = 'user' WHERE "role" = 'admin';
+
+ALTER TYPE user_role RENAME TO user_role_old;
+CREATE TYPE user_role AS ENUM ('user', 'merchant');
+
+ALTER TABLE "auth" ALTER COLUMN "role" DROP DEFAULT;
+ALTER TABLE "auth" ALTER COLUMN "role" TYPE user_role USING "role"::text::user_role;
+ALTER TABLE "auth" ALTER COLUMN "role" SET DEFAULT 'user';
+
+DROP TYPE user_role_old;
+-- +goose StatementEnd
@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE "products" ADD COLUMN "search_vector" TSVECTOR;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION products_search_vector_update() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', coalesce(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce((SELECT name FROM categories WHERE id = NEW.category_id), '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(NEW.description, '')), 'C');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER products_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF name, description, category_id ON "products"
+    FOR EACH ROW EXECUTE FUNCTION products_search_vector_update();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION categories_search_vector_refresh() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE products SET category_id = category_id WHERE category_id = NEW.id;
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER categories_search_vector_trigger
+    AFTER UPDATE OF name ON "categories"
+    FOR EACH ROW EXECUTE FUNCTION categories_search_vector_refresh();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE "products" SET category_id = category_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS "products_search_vector_idx" ON "products" USING GIN ("search_vector");
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS "products_name_trgm_idx" ON "products" USING GIN ("name" gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "products_name_trgm_idx";
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "products_search_vector_idx";
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS categories_search_vector_trigger ON "categories";
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS categories_search_vector_refresh();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS products_search_vector_trigger ON "products";
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS products_search_vector_update();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE "products" DROP COLUMN IF EXISTS "search_vector";
+-- +goose StatementEnd
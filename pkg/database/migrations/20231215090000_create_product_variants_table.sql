@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "product_options" (
+    "id" SERIAL PRIMARY KEY,
+    "product_id" INTEGER NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "name" VARCHAR(100) NOT NULL,
+    "option_values" JSONB NOT NULL,
+    "position" INTEGER NOT NULL DEFAULT 0,
+    UNIQUE ("product_id", "name")
+);
+
+CREATE TABLE IF NOT EXISTS "product_variants" (
+    "id" SERIAL PRIMARY KEY,
+    "product_id" INTEGER NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "sku" VARCHAR(255) NOT NULL UNIQUE,
+    "options" JSONB NOT NULL,
+    "price" INTEGER NULL,
+    "stock" INTEGER NOT NULL DEFAULT 0 CHECK ("stock" >= 0),
+    "image_url" VARCHAR(255) NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP NULL,
+    UNIQUE ("product_id", "options")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "product_variants";
+DROP TABLE IF EXISTS "product_options";
+-- +goose StatementEnd
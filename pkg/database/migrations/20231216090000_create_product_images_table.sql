@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "product_images" (
+    "id" SERIAL PRIMARY KEY,
+    "product_id" INTEGER NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "image_url" VARCHAR(255) NOT NULL,
+    "alt_text" VARCHAR(255) NOT NULL DEFAULT '',
+    "position" INTEGER NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "product_images_product_id_position_idx" ON "product_images" ("product_id", "position");
+
+-- the existing single image becomes the first entry of every gallery
+INSERT INTO "product_images" ("product_id", "image_url", "position")
+SELECT "id", "image_url", 0 FROM "products" WHERE "image_url" <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "product_images";
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE inventory_movement_type AS ENUM ('restock', 'sale', 'adjustment', 'return', 'reservation');
+
+CREATE TABLE IF NOT EXISTS "inventory_movements" (
+    "id" SERIAL PRIMARY KEY,
+    "product_id" INTEGER NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "variant_id" INTEGER NULL REFERENCES "product_variants" ("id") ON DELETE SET NULL,
+    "type" inventory_movement_type NOT NULL,
+    "quantity" INTEGER NOT NULL CHECK ("quantity" <> 0),
+    "stock_after" INTEGER NOT NULL,
+    "reason" VARCHAR(255) NOT NULL DEFAULT '',
+    "created_by" UUID NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "inventory_movements_product_id_idx" ON "inventory_movements" ("product_id", "id" DESC);
+
+-- the stock that exists before the ledger becomes its opening balance
+INSERT INTO "inventory_movements" ("product_id", "type", "quantity", "stock_after", "reason", "created_by")
+SELECT "id", 'adjustment', "stock", "stock", 'opening balance', "created_by" FROM "products" WHERE "stock" <> 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "inventory_movements";
+DROP TYPE IF EXISTS inventory_movement_type;
+-- +goose StatementEnd
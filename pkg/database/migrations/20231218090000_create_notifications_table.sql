@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "products"
+    ADD COLUMN "low_stock_threshold" INTEGER NOT NULL DEFAULT 0 CHECK ("low_stock_threshold" >= 0),
+    ADD COLUMN "low_stock_alerted_at" TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" SERIAL PRIMARY KEY,
+    "merchant_id" INTEGER NOT NULL REFERENCES "merchants" ("id") ON DELETE CASCADE,
+    "product_id" INTEGER NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "type" VARCHAR(50) NOT NULL,
+    "title" VARCHAR(255) NOT NULL,
+    "message" TEXT NOT NULL,
+    "stock" INTEGER NOT NULL,
+    "threshold" INTEGER NOT NULL,
+    "read_at" TIMESTAMP NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "notifications_merchant_id_idx" ON "notifications" ("merchant_id", "id" DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "notifications";
+ALTER TABLE "products"
+    DROP COLUMN IF EXISTS "low_stock_alerted_at",
+    DROP COLUMN IF EXISTS "low_stock_threshold";
+-- +goose StatementEnd
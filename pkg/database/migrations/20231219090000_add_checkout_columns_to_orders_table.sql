@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "orders" ADD PRIMARY KEY ("id");
+ALTER TABLE "orders" ADD CONSTRAINT "orders_trx_id_key" UNIQUE ("trx_id");
+
+-- the line keeps what was bought at which price, products can change or be purged later
+ALTER TABLE "order_details"
+    ADD COLUMN "sku" VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN "product_name" VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN "price" INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT "order_details_order_id_fkey" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS "orders_user_id_idx" ON "orders" ("user_id", "created_at" DESC);
+CREATE INDEX IF NOT EXISTS "order_details_order_id_idx" ON "order_details" ("order_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "order_details_order_id_idx";
+DROP INDEX IF EXISTS "orders_user_id_idx";
+ALTER TABLE "order_details"
+    DROP CONSTRAINT IF EXISTS "order_details_order_id_fkey",
+    DROP COLUMN IF EXISTS "price",
+    DROP COLUMN IF EXISTS "product_name",
+    DROP COLUMN IF EXISTS "sku";
+ALTER TABLE "orders" DROP CONSTRAINT IF EXISTS "orders_trx_id_key";
+ALTER TABLE "orders" DROP CONSTRAINT IF EXISTS "orders_pkey";
+-- +goose StatementEnd
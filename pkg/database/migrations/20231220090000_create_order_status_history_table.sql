@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE order_status AS ENUM (
+    'PENDING_PAYMENT', 'PAID', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'COMPLETED',
+    'CANCELLED', 'REFUNDED', 'EXPIRED'
+);
+
+ALTER TABLE "orders" ALTER COLUMN "status" TYPE order_status
+    USING (CASE "status" WHEN 'UNPAID' THEN 'PENDING_PAYMENT' ELSE "status"::TEXT END)::order_status;
+
+DROP TYPE IF EXISTS payment_status;
+
+-- actor_id is NULL for moves made by the system, e.g. a payment callback or expiry
+CREATE TABLE IF NOT EXISTS "order_status_history" (
+    "id" SERIAL PRIMARY KEY,
+    "order_id" VARCHAR(255) NOT NULL REFERENCES "orders" ("id") ON DELETE CASCADE,
+    "from_status" order_status NULL,
+    "to_status" order_status NOT NULL,
+    "actor_id" UUID NULL REFERENCES "auth" ("id") ON DELETE SET NULL,
+    "actor" VARCHAR(50) NOT NULL,
+    "reason" VARCHAR(255) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "order_status_history_order_id_idx" ON "order_status_history" ("order_id", "id");
+
+INSERT INTO "order_status_history" ("order_id", "to_status", "actor_id", "actor", "created_at")
+SELECT "id", "status", "created_by", 'buyer', "created_at" FROM "orders";
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "order_status_history";
+
+CREATE TYPE payment_status AS ENUM ('UNPAID', 'PAID', 'EXPIRED');
+
+ALTER TABLE "orders" ALTER COLUMN "status" TYPE payment_status
+    USING (CASE
+        WHEN "status" = 'PENDING_PAYMENT' THEN 'UNPAID'
+        WHEN "status" IN ('CANCELLED', 'EXPIRED') THEN 'EXPIRED'
+        ELSE 'PAID'
+    END)::payment_status;
+
+DROP TYPE IF EXISTS order_status;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- an order belongs to exactly one merchant, older orders spanning several merchants stay NULL and only admins can move them
+ALTER TABLE "orders" ADD COLUMN "merchant_id" INTEGER NULL REFERENCES "merchants" ("id") ON DELETE SET NULL;
+
+UPDATE "orders" o SET "merchant_id" = m."merchant_id"
+FROM (
+    SELECT d."order_id", MIN(p."merchant_id") AS "merchant_id"
+    FROM "order_details" d
+    JOIN "products" p ON p."id" = d."product_id"
+    GROUP BY d."order_id"
+    HAVING COUNT(DISTINCT p."merchant_id") = 1
+) m
+WHERE m."order_id" = o."id";
+
+CREATE INDEX IF NOT EXISTS "orders_merchant_id_idx" ON "orders" ("merchant_id", "created_at" DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "orders_merchant_id_idx";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "merchant_id";
+-- +goose StatementEnd
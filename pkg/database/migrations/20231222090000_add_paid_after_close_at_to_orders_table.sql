@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- set when the gateway reports a payment for an order that was already cancelled or expired, the buyer is owed a refund
+ALTER TABLE "orders" ADD COLUMN "paid_after_close_at" TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS "orders_paid_after_close_at_idx" ON "orders" ("paid_after_close_at") WHERE "paid_after_close_at" IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "orders_paid_after_close_at_idx";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "paid_after_close_at";
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- taken from the invoice, the order expires once the invoice can no longer be paid
+ALTER TABLE "orders" ADD COLUMN "payment_expires_at" TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS "orders_pending_payment_idx" ON "orders" ("created_at") WHERE "status" = 'PENDING_PAYMENT';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "orders_pending_payment_idx";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "payment_expires_at";
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a user owns at most one merchant, concurrent onboarding requests must not create a second one
+CREATE UNIQUE INDEX IF NOT EXISTS "merchants_created_by_key" ON "merchants" ("created_by") WHERE "deleted_at" IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "merchants_created_by_key";
+-- +goose StatementEnd
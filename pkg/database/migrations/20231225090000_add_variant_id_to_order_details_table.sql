@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- like product_id this is kept without a foreign key, the variant can be removed later
+ALTER TABLE "order_details" ADD COLUMN "variant_id" INTEGER NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "order_details" DROP COLUMN IF EXISTS "variant_id";
+-- +goose StatementEnd